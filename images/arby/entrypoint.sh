@@ -1,4 +1,14 @@
 #!/bin/bash
 set -m
+
+# The launcher passes CEX_API_SECRET when it creates the container, the
+# generated docker-compose.yml only names it. A container recreated by a
+# manual "docker-compose up" has no secret, recreate it with "launcher up"
+# instead. "launcher restart" and "docker restart" keep it.
+if [[ -z ${CEX_API_SECRET:-} ]]; then
+    echo "CEX_API_SECRET is empty, the container was not created by the launcher. Recreate it with \"launcher up\"." >&2
+    exit 1
+fi
+
 # use exec to properly respond to SIGINT
 exec npm run start:arby
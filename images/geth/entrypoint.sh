@@ -13,11 +13,11 @@ OPTS=(
   "--http.addr 0.0.0.0"
   "--http.api eth,net"
   "--http.vhosts=*"
-  "--cache=256"
+  "--cache=${CACHE:-256}"
   "--nousb"
 )
 
-if [[ $CUSTOM_ANCIENT_CHAINDATA == "true" ]]; then
+if [[ ${CUSTOM_ANCIENT_CHAINDATA:-false} == "true" ]]; then
     OPTS+=("--datadir.ancient=/root/.ethereum-ancient-chaindata")
 fi
 
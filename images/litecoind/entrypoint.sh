@@ -2,7 +2,7 @@
 
 set -euo pipefail
 
-LITECOIN_DIR=$HOME/.litecoin
+LITECOIN_DIR=${DATA_DIR:-$HOME/.litecoin}
 
 if [[ $NETWORK == "testnet" ]]; then
     LOGFILE=$LITECOIN_DIR/testnet4/debug.log
@@ -23,10 +23,11 @@ ERRFILE="/tmp/launch.err"
 
 DEFAULT_OPTS=(
     "-server"
+    "-datadir=$LITECOIN_DIR"
     "-rpcuser=${RPC_USER:-${DEFAULT_RPC_USER}}"
     "-rpcpassword=${RPC_PASSWORD:-${DEFAULT_RPC_PASSWORD}}"
     "-disablewallet"
-    "-txindex"
+    "-txindex=${TXINDEX:-1}"
     "-zmqpubrawblock=tcp://0.0.0.0:29332"
     "-zmqpubrawtx=tcp://0.0.0.0:29333"
     "-logips"
@@ -39,6 +40,11 @@ if [[ $NETWORK == "testnet" ]]; then
     DEFAULT_OPTS+=("-testnet")
 fi
 
+if [[ -n ${EXTRA_OPTS:-} ]]; then
+    read -ra EXTRA <<< "$EXTRA_OPTS"
+    DEFAULT_OPTS+=("${EXTRA[@]}")
+fi
+
 OPTS=("${DEFAULT_OPTS[@]}")
 
 function start_litecoind() {
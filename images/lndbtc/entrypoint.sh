@@ -60,31 +60,44 @@ set +e
 
 set -e
 
-LND_HOSTNAME="$HOME/.lnd/tor/hostname"
-echo "Waiting for lnd-$CHAIN onion address..."
+case $CHAIN in
+  bitcoin)
+    PORT=9735
+    ;;
+  litecoin)
+    PORT=10735
+    ;;
+esac
 
-wait_file "$LND_HOSTNAME" && {
+case $NETWORK in
+  testnet)
+    PORT=$((PORT + 10000))
+    ;;
+esac
+
+EXTERNAL_IPS=()
+
+if [[ ${TOR_DISABLED:-} == "true" ]]; then
+  echo "Tor is disabled, lnd-$CHAIN runs in clearnet-only mode"
+  sed -i "s/tor.active=.*/tor.active=0/g" $LND_DIR/lnd.conf
+elif [[ -n ${TOR_PROXY:-} ]]; then
+  echo "lnd-$CHAIN connects through the tor proxy $TOR_PROXY"
+  sed -i "s/tor.active=.*/tor.active=1/g; s/tor.socks=.*/tor.socks=$TOR_PROXY/g" $LND_DIR/lnd.conf
+  # lnd only talks to the tor control port to create onion services with
+  # tor.v2 or tor.v3, the proxy mode has no onion address
+  sed -i "/^tor\.\(v2\|v3\|control\)=/d" $LND_DIR/lnd.conf
+else
+  sed -i "s/tor.active=.*/tor.active=1/g; s/tor.socks=.*/tor.socks=9050/g" $LND_DIR/lnd.conf
+  LND_HOSTNAME="$HOME/.lnd/tor/hostname"
+  echo "Waiting for lnd-$CHAIN onion address..."
+  wait_file "$LND_HOSTNAME" || exit 1
   LND_ONION_ADDRESS=$(cat "$LND_HOSTNAME")
   echo "Onion address for lnd-$CHAIN is $LND_ONION_ADDRESS"
+  EXTERNAL_IPS+=("--externalip=$LND_ONION_ADDRESS:$PORT")
+fi
 
-  case $CHAIN in
-    bitcoin)
-      PORT=9735
-      ;;
-    litecoin)
-      PORT=10735
-      ;;
-  esac
-
-  case $NETWORK in
-    testnet)
-      PORT=$((PORT + 10000))
-      ;;
-  esac
+if [[ -n ${EXTERNAL_IP:-} ]]; then
+  EXTERNAL_IPS+=("--externalip=$EXTERNAL_IP:$PORT")
+fi
 
-  if [ -z ${EXTERNAL_IP+x} ]; then
-    lnd --$CHAIN.$NETWORK --lnddir=$LND_DIR --externalip="$LND_ONION_ADDRESS:$PORT" --listen="0.0.0.0:$PORT"
-  else
-    lnd --$CHAIN.$NETWORK --lnddir=$LND_DIR --externalip="$LND_ONION_ADDRESS:$PORT" --externalip="$EXTERNAL_IP:$PORT" --listen="0.0.0.0:$PORT"
-  fi
-} || exit 1
+lnd --$CHAIN.$NETWORK --lnddir=$LND_DIR ${EXTERNAL_IPS[@]+"${EXTERNAL_IPS[@]}"} --listen="0.0.0.0:$PORT"
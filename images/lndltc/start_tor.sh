@@ -1,5 +1,11 @@
 #!/usr/bin/env bash
 
+if [[ ${TOR_DISABLED:-} == "true" || -n ${TOR_PROXY:-} ]]; then
+    echo "Bundled tor is not used"
+    # keep supervisord from restarting this program
+    exec sleep infinity
+fi
+
 case $CHAIN in
     bitcoin)
         PORT=9735
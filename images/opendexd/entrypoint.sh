@@ -40,7 +40,20 @@ RPC_PORT="${RPC_PORT:-$DEFAULT_RPC_PORT}"
 HTTP_PORT="${HTTP_PORT:-$DEFAULT_HTTP_PORT}"
 
 
-[[ -e ${TOR_TORRC} ]] || cat <<EOF >/etc/tor/torrc
+if [[ ${TOR_DISABLED:-} == "true" ]]; then
+    echo "[entrypoint] Tor is disabled, opendexd runs in clearnet-only mode"
+    TOR_ENABLED=false
+    XUD_ADDRESSES=()
+elif [[ -n ${TOR_PROXY:-} ]]; then
+    # opendexd only dials the SOCKS proxy on localhost at torport
+    echo "[entrypoint] opendexd connects through the tor proxy $TOR_PROXY"
+    TOR_ENABLED=true
+    XUD_ADDRESSES=()
+    socat TCP-LISTEN:9050,bind=127.0.0.1,fork,reuseaddr "TCP:$TOR_PROXY" &
+else
+    TOR_ENABLED=true
+
+    [[ -e ${TOR_TORRC} ]] || cat <<EOF >/etc/tor/torrc
 DataDirectory $TOR_DATA_DIR
 ExitPolicy reject *:* # no exits allowed
 HiddenServiceDir $TOR_DIR
@@ -48,18 +61,22 @@ HiddenServicePort $P2P_PORT 127.0.0.1:$P2P_PORT
 HiddenServiceVersion 3
 EOF
 
-tor -f $TOR_TORRC &
+    tor -f $TOR_TORRC &
 
-if [[ ! -z "$LND_HOSTNAME_FILE" ]]; then
     while [[ ! -e "$LND_HOSTNAME_FILE" ]]; do
         echo "[entrypoint] Waiting for opendexd onion address at $LND_HOSTNAME_FILE"
         sleep 1
     done
 
     XUD_ADDRESS=$(cat "$LND_HOSTNAME_FILE")
+    echo "[entrypoint] Onion address for opendexd is $XUD_ADDRESS"
+    XUD_ADDRESSES=("\"$XUD_ADDRESS\"")
 fi
 
-echo "[entrypoint] Onion address for opendexd is $XUD_ADDRESS"
+if [[ -n ${EXTERNAL_IP:-} ]]; then
+    echo "[entrypoint] External address for opendexd is $EXTERNAL_IP:$P2P_PORT"
+    XUD_ADDRESSES+=("\"$EXTERNAL_IP:$P2P_PORT\"")
+fi
 
 
 echo '[entrypoint] Detecting localnet IP for lndbtc...'
@@ -101,10 +118,6 @@ fi
     sed -i '/\[lnd\.LTC/,/^$/s/host.*/host = "lndltc"/' $XUD_CONF
     sed -i '/\[lnd\.LTC/,/^$/s/port.*/port = 10009/' $XUD_CONF
     sed -i "/\[lnd\.LTC/,/^$/s|^$|certpath = \"/root/.lndltc/tls.cert\"\nmacaroonpath = \"/root/.lndltc/data/chain/litecoin/$NETWORK/admin.macaroon\"\n|" $XUD_CONF
-    sed -i "/\[p2p/,/^$/s/addresses.*/addresses = \[\"$XUD_ADDRESS\"]/" $XUD_CONF
-    sed -i "/\[p2p/,/^$/s/port.*/port = $P2P_PORT/" $XUD_CONF
-    sed -i '/\[p2p/,/^$/s/tor = .*/tor = true/' $XUD_CONF
-    sed -i '/\[p2p/,/^$/s/torport.*/torport = 9050/' $XUD_CONF
     sed -i '/\[raiden/,/^$/s/disable.*/disable = true/' $XUD_CONF
     sed -i '/\[rpc/,/^$/s/host.*/host = "0.0.0.0"/' $XUD_CONF
     sed -i "/\[rpc/,/^$/s/port.*/port = $RPC_PORT/" $XUD_CONF
@@ -115,6 +128,14 @@ fi
     sed -i "/\[connext/,/^$/s/webhookport.*/webhookport = $HTTP_PORT/" $XUD_CONF
 }
 
+# the p2p addresses and the tor mode follow the launcher settings even when
+# the config is preserved
+XUD_ADDRESSES_LIST=$(IFS=,; echo "${XUD_ADDRESSES[*]+${XUD_ADDRESSES[*]}}")
+sed -i "/\[p2p/,/^$/s/addresses.*/addresses = \[$XUD_ADDRESSES_LIST]/" $XUD_CONF
+sed -i "/\[p2p/,/^$/s/port.*/port = $P2P_PORT/" $XUD_CONF
+sed -i "/\[p2p/,/^$/s/tor = .*/tor = $TOR_ENABLED/" $XUD_CONF
+sed -i '/\[p2p/,/^$/s/torport.*/torport = 9050/' $XUD_CONF
+
 echo "[entrypoint] Launch with opendexd.conf:"
 cat $XUD_CONF
 
@@ -1,5 +1,11 @@
 #!/bin/bash
 
 XUD_BACKUP_DIR="${XUD_BACKUP_DIR:-/root/backup}"
+# the launcher alerts on the failure recorded in this file
+BACKUP_FAILED_FILE=/root/.opendex/backup.failed
+rm -f $BACKUP_FAILED_FILE
 echo "[backup] Initiating backup to $XUD_BACKUP_DIR..."
 ./bin/opendex-backup -b $XUD_BACKUP_DIR
+code=$?
+echo "[backup] opendex-backup exited with code $code"
+echo "opendex-backup exited with code $code" > $BACKUP_FAILED_FILE
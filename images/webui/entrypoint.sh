@@ -20,5 +20,7 @@ while ! [ -e /root/.opendex/tls.cert ]; do
     sleep 1
 done
 
-exec bin/server --opendexd.rpchost=ond --opendexd.rpcport=$RPCPORT --opendexd.rpccert=/root/.opendex/tls.cert \
+# bin/server only talks to opendexd, gateway.js publishes it on $PORT with
+# the access token and the proxy API
+PORT=${SERVER_PORT:-8080} exec bin/server --opendexd.rpchost=opendexd --opendexd.rpcport=$RPCPORT --opendexd.rpccert=/root/.opendex/tls.cert \
 --pairs.weight btc_usdt:4,eth_btc:3,ltc_btc:2,ltc_usdt:1
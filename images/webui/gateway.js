@@ -0,0 +1,194 @@
+// The gateway is the published endpoint of the webui. bin/server reads none of
+// the launcher's settings, so the gateway checks ACCESS_TOKEN, forwards /api/
+// to the proxy at PROXY_URL (trusting PROXY_TLS_CERT) and everything else,
+// including the socket.io upgrades, to bin/server.
+
+const crypto = require("crypto");
+const fs = require("fs");
+const http = require("http");
+const https = require("https");
+const net = require("net");
+
+const port = parseInt(process.env.PORT || "8081", 10);
+const serverPort = parseInt(process.env.SERVER_PORT || "8080", 10);
+const accessToken = process.env.ACCESS_TOKEN || "";
+const proxyUrl = process.env.PROXY_URL ? new URL(process.env.PROXY_URL) : null;
+
+const cookieName = "webui_token";
+
+if (!accessToken) {
+    console.error("ACCESS_TOKEN is not set");
+    process.exit(1);
+}
+
+function equal(a, b) {
+    const x = Buffer.from(a);
+    const y = Buffer.from(b);
+    return x.length === y.length && crypto.timingSafeEqual(x, y);
+}
+
+function getCookie(req, name) {
+    for (const part of (req.headers.cookie || "").split(";")) {
+        const [key, ...value] = part.trim().split("=");
+        if (key === name) {
+            return decodeURIComponent(value.join("="));
+        }
+    }
+    return "";
+}
+
+function authorized(req) {
+    const auth = req.headers.authorization || "";
+    if (auth.startsWith("Bearer ") && equal(auth.slice(7), accessToken)) {
+        return true;
+    }
+    return equal(getCookie(req, cookieName), accessToken);
+}
+
+function fingerprint(pem) {
+    const der = Buffer.from(pem.toString().replace(/-----[^-]+-----/g, "").replace(/\s+/g, ""), "base64");
+    return crypto.createHash("sha256").update(der).digest("hex");
+}
+
+// readProxyCerts loads the proxy certificate and the previous one a running
+// proxy may still serve. The proxy may not have created them yet when the
+// gateway starts, and the launcher rotates them without restarting the
+// webui, so they are read again whenever the certificate file changes.
+const proxyCertPath = process.env.PROXY_TLS_CERT;
+let proxyCerts = {mtime: -1, ca: [], fingerprints: []};
+function readProxyCerts() {
+    const mtime = fs.statSync(proxyCertPath).mtimeMs;
+    if (mtime !== proxyCerts.mtime) {
+        const ca = [fs.readFileSync(proxyCertPath)];
+        try {
+            ca.push(fs.readFileSync(`${proxyCertPath}.old`));
+        } catch (err) {
+            // there is no previous certificate
+        }
+        proxyCerts = {mtime: mtime, ca: ca, fingerprints: ca.map(fingerprint)};
+    }
+    return proxyCerts;
+}
+
+// proxyTls returns the TLS options of the proxy requests. The certificate is
+// pinned instead of checking the hostname, as a user supplied one may not
+// name the "proxy" host.
+function proxyTls() {
+    const {ca, fingerprints} = readProxyCerts();
+    return {
+        ca: ca,
+        checkServerIdentity: (hostname, cert) => {
+            if (fingerprints.includes(cert.fingerprint256.replace(/:/g, "").toLowerCase())) {
+                return undefined;
+            }
+            return new Error("the proxy certificate doesn't match the pinned one");
+        },
+    };
+}
+
+// forward sends req to the target without the webui credentials
+function forward(req, res, target) {
+    const headers = Object.assign({}, req.headers);
+    delete headers.cookie;
+    delete headers.authorization;
+    headers.host = target.host;
+    let tls;
+    try {
+        tls = target.tls && target.tls();
+    } catch (err) {
+        res.writeHead(503);
+        res.end(`${err.message}\n`);
+        return;
+    }
+    const client = target.protocol === "https:" ? https : http;
+    const upstream = client.request(Object.assign({
+        protocol: target.protocol,
+        hostname: target.hostname,
+        port: target.port,
+        path: req.url,
+        method: req.method,
+        headers: headers,
+    }, tls), (r) => {
+        res.writeHead(r.statusCode, r.headers);
+        r.pipe(res);
+    });
+    upstream.on("error", (err) => {
+        console.error(`Forward ${req.method} ${req.url}: ${err.message}`);
+        if (!res.headersSent) {
+            res.writeHead(502);
+        }
+        res.end();
+    });
+    req.pipe(upstream);
+}
+
+const server = {protocol: "http:", hostname: "127.0.0.1", port: serverPort, host: `127.0.0.1:${serverPort}`};
+const proxy = proxyUrl && {
+    protocol: proxyUrl.protocol,
+    hostname: proxyUrl.hostname,
+    port: proxyUrl.port,
+    host: proxyUrl.host,
+    tls: proxyCertPath && proxyTls,
+};
+
+const gateway = http.createServer((req, res) => {
+    const url = new URL(req.url, "http://localhost");
+    const token = url.searchParams.get("token");
+    if (token !== null) {
+        // the token of the "webui open" URL is swapped for a cookie
+        if (!equal(token, accessToken)) {
+            res.writeHead(401);
+            res.end("Unauthorized\n");
+            return;
+        }
+        url.searchParams.delete("token");
+        res.writeHead(303, {
+            "Location": url.pathname + url.search,
+            "Set-Cookie": `${cookieName}=${encodeURIComponent(accessToken)}; Path=/; HttpOnly; SameSite=Strict`,
+        });
+        res.end();
+        return;
+    }
+    if (!authorized(req)) {
+        res.writeHead(401);
+        res.end("Unauthorized, open the URL printed by \"launcher webui open\"\n");
+        return;
+    }
+    if (url.pathname.startsWith("/api/")) {
+        if (!proxy) {
+            res.writeHead(503);
+            res.end("PROXY_URL is not set\n");
+            return;
+        }
+        forward(req, res, proxy);
+        return;
+    }
+    forward(req, res, server);
+});
+
+gateway.on("upgrade", (req, socket, head) => {
+    if (!authorized(req)) {
+        socket.end("HTTP/1.1 401 Unauthorized\r\n\r\n");
+        return;
+    }
+    const upstream = net.connect(serverPort, "127.0.0.1", () => {
+        const lines = [`${req.method} ${req.url} HTTP/${req.httpVersion}`];
+        for (let i = 0; i < req.rawHeaders.length; i += 2) {
+            const key = req.rawHeaders[i].toLowerCase();
+            if (key === "cookie" || key === "authorization") {
+                continue;
+            }
+            lines.push(`${req.rawHeaders[i]}: ${req.rawHeaders[i + 1]}`);
+        }
+        upstream.write(lines.join("\r\n") + "\r\n\r\n");
+        upstream.write(head);
+        upstream.pipe(socket);
+        socket.pipe(upstream);
+    });
+    upstream.on("error", () => socket.destroy());
+    socket.on("error", () => upstream.destroy());
+});
+
+gateway.listen(port, () => {
+    console.log(`Gateway listening on ${port}, forwarding to bin/server on ${serverPort}`);
+});
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	alertsCmd.AddCommand(alertsTestCmd)
+	rootCmd.AddCommand(alertsCmd)
+}
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Manage alerts configured in alerts.json",
+}
+
+var alertsTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test alert to all notifiers",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext()
+		defer cancel()
+		if err := launcher.TestAlerts(ctx); err != nil {
+			return err
+		}
+		fmt.Println("Test alert sent")
+		return nil
+	},
+}
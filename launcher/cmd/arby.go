@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/arby"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type ArbyReportOptions struct {
+	Csv   string
+	Json  bool
+	Limit int
+}
+
+var (
+	arbyReportOpts ArbyReportOptions
+)
+
+func init() {
+	arbyReportCmd.PersistentFlags().StringVar(&arbyReportOpts.Csv, "csv", "", "export the trade history as CSV into this file (- for stdout)")
+	arbyReportCmd.PersistentFlags().BoolVar(&arbyReportOpts.Json, "json", false, "print the report as JSON")
+	arbyReportCmd.PersistentFlags().IntVar(&arbyReportOpts.Limit, "limit", 0, "number of latest trades to list and export (0 for all), the opendexd PnL always covers all trades of the pair")
+	arbyCmd.AddCommand(arbyReportCmd)
+	rootCmd.AddCommand(arbyCmd)
+}
+
+var arbyCmd = &cobra.Command{
+	Use:   "arby",
+	Short: "Inspect the arbitrage bot",
+}
+
+func writeTradesCsv(w io.Writer, report *arby.Report) error {
+	c := csv.NewWriter(w)
+	if err := c.Write([]string{"executed_at", "pair", "side", "role", "price", "quantity", "rhash"}); err != nil {
+		return err
+	}
+	for _, trade := range report.Trades {
+		err := c.Write([]string{
+			trade.ExecutedAt.UTC().Format(time.RFC3339),
+			trade.PairId,
+			trade.Side,
+			trade.Role,
+			strconv.FormatFloat(trade.Price, 'f', -1, 64),
+			formatAmount(trade.Quantity),
+			trade.RHash,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+func exportTradesCsv(path string, report *arby.Report) error {
+	if path == "-" {
+		return writeTradesCsv(os.Stdout, report)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeTradesCsv(f, report); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d trades to %s\n", len(report.Trades), path)
+	return nil
+}
+
+var arbyReportCmd = &cobra.Command{
+	Use:   "report [INSTANCE]",
+	Short: "Show the connections and open orders of arby or an arby instance (e.g. eth-btc) and the opendexd trades and PnL of its pair",
+	Args:  cobra.MaximumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext()
+		defer cancel()
+
+		name := "arby"
+		if len(args) == 1 && args[0] != name {
+			name = "arby-" + strings.TrimPrefix(args[0], "arby-")
+		}
+
+		report, err := launcher.GetArbyReport(ctx, name, arbyReportOpts.Limit)
+		if err != nil {
+			return err
+		}
+
+		if arbyReportOpts.Csv != "" {
+			return exportTradesCsv(arbyReportOpts.Csv, report)
+		}
+
+		if arbyReportOpts.Json {
+			return printJson(report)
+		}
+
+		quote := report.QuoteAsset()
+		fmt.Printf("Pair: %s\n", report.Pair)
+		fmt.Printf("opendexd: %s\n", report.Opendexd)
+		fmt.Printf("CEX (%s): %s\n", report.Cex, report.CexConnection)
+		fmt.Printf("opendexd %s position: %+.8f\n", report.Pair, report.OpendexdPosition)
+		fmt.Printf("opendexd %s realized PnL: %+.8f %s\n", report.Pair, report.OpendexdRealizedPnl, quote)
+		fmt.Printf("opendexd %s unrealized PnL: %+.8f %s\n", report.Pair, report.OpendexdUnrealizedPnl, quote)
+		fmt.Println("(all opendexd trades of the pair, arby's or not, without the CEX leg: not the arbitrage PnL)")
+		fmt.Println()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "OPEN ORDER\tSIDE\tPRICE\tQUANTITY\tCREATED")
+		for _, o := range report.OpenOrders {
+			fmt.Fprintf(w, "%s\t%s\t%v\t%s\t%s\n", o.Id, o.Side, o.Price, formatAmount(o.Quantity), o.CreatedAt.Format(time.RFC3339))
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "EXECUTED\tSIDE\tROLE\tPRICE\tQUANTITY")
+		for _, trade := range report.Trades {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\n", trade.ExecutedAt.Format(time.RFC3339), trade.Side, trade.Role, trade.Price, formatAmount(trade.Quantity))
+		}
+		return w.Flush()
+	},
+}
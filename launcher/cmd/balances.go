@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+type BalancesOptions struct {
+	Json bool
+}
+
+var (
+	balancesOpts BalancesOptions
+)
+
+func init() {
+	balancesCmd.PersistentFlags().BoolVar(&balancesOpts.Json, "json", false, "print balances as JSON")
+	rootCmd.AddCommand(balancesCmd)
+}
+
+// formatAmount formats satoshis (1e-8 of a coin) in coin units
+func formatAmount(satoshis uint64) string {
+	return fmt.Sprintf("%d.%08d", satoshis/1e8, satoshis%1e8)
+}
+
+func printJson(v interface{}) error {
+	j, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(j))
+	return nil
+}
+
+// printErrors reports the services which couldn't be queried
+func printErrors(errs map[string]string) {
+	var names []string
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "Failed to query %s: %s\n", name, errs[name])
+	}
+}
+
+var balancesCmd = &cobra.Command{
+	Use:   "balances",
+	Short: "Show wallet and channel balances of all currencies",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext()
+		defer cancel()
+
+		overview, err := launcher.GetOverview(ctx)
+		if err != nil {
+			return err
+		}
+
+		if balancesOpts.Json {
+			return printJson(map[string]interface{}{
+				"balances": overview.Balances,
+				"errors":   overview.Errors,
+			})
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CURRENCY\tWALLET\tUNCONFIRMED\tCAPACITY\tLOCAL\tREMOTE\tPENDING\tMAX SELL\tMAX BUY")
+		for _, b := range overview.Balances {
+			pending := formatAmount(b.PendingLocalBalance)
+			if b.PendingChannels > 0 {
+				pending = fmt.Sprintf("%s (%d)", pending, b.PendingChannels)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				b.Currency,
+				formatAmount(b.WalletBalance),
+				formatAmount(b.UnconfirmedWalletBalance),
+				formatAmount(b.ChannelCapacity),
+				formatAmount(b.LocalBalance),
+				formatAmount(b.RemoteBalance),
+				pending,
+				formatAmount(b.MaxSell),
+				formatAmount(b.MaxBuy),
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		printErrors(overview.Errors)
+		return nil
+	},
+}
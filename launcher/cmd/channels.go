@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"text/tabwriter"
+)
+
+type ChannelsOptions struct {
+	Json bool
+}
+
+var (
+	channelsOpts ChannelsOptions
+)
+
+func init() {
+	channelsCmd.PersistentFlags().BoolVar(&channelsOpts.Json, "json", false, "print channels as JSON")
+	rootCmd.AddCommand(channelsCmd)
+}
+
+var channelsCmd = &cobra.Command{
+	Use:   "channels",
+	Short: "Show the payment channels of lndbtc, lndltc and connext",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext()
+		defer cancel()
+
+		overview, err := launcher.GetOverview(ctx)
+		if err != nil {
+			return err
+		}
+
+		if channelsOpts.Json {
+			return printJson(map[string]interface{}{
+				"channels": overview.Channels,
+				"errors":   overview.Errors,
+			})
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CURRENCY\tSERVICE\tSTATE\tCAPACITY\tLOCAL\tREMOTE\tPEER\tCHANNEL")
+		for _, c := range overview.Channels {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				c.Currency,
+				c.Service,
+				c.State,
+				formatAmount(c.Capacity),
+				formatAmount(c.LocalBalance),
+				formatAmount(c.RemoteBalance),
+				c.Peer,
+				c.Id,
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		printErrors(overview.Errors)
+		return nil
+	},
+}
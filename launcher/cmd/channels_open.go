@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/core"
+	"github.com/spf13/cobra"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type OpenChannelsOptions struct {
+	Currencies []string
+	Peer       string
+	Amounts    []string
+	Yes        bool
+	Wait       time.Duration
+}
+
+var (
+	openChannelsOpts OpenChannelsOptions
+)
+
+func init() {
+	openChannelsCmd.PersistentFlags().StringSliceVar(&openChannelsOpts.Currencies, "currency", core.ChannelCurrencies, "currencies to open channels for")
+	openChannelsCmd.PersistentFlags().StringVar(&openChannelsOpts.Peer, "peer", "", "opendex peer pubkey or alias (default: the first peer supporting the currency)")
+	openChannelsCmd.PersistentFlags().StringSliceVar(&openChannelsOpts.Amounts, "amount", []string{}, "channel amounts in coins overriding the suggested ones (e.g. BTC=0.01)")
+	openChannelsCmd.PersistentFlags().BoolVarP(&openChannelsOpts.Yes, "yes", "y", false, "open the channels without confirmation")
+	openChannelsCmd.PersistentFlags().DurationVar(&openChannelsOpts.Wait, "wait", 2*time.Hour, "how long to wait for the channels to become active (0 doesn't wait)")
+	channelsCmd.AddCommand(openChannelsCmd)
+}
+
+// parseCoins parses an amount in coin units (e.g. "0.01") into satoshis
+func parseCoins(s string) (uint64, error) {
+	parts := strings.SplitN(s, ".", 2)
+	whole, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount: %s", s)
+	}
+	var frac uint64
+	if len(parts) == 2 {
+		f := parts[1]
+		if len(f) > 8 {
+			return 0, fmt.Errorf("too many decimals: %s", s)
+		}
+		f += strings.Repeat("0", 8-len(f))
+		frac, err = strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount: %s", s)
+		}
+	}
+	return whole*1e8 + frac, nil
+}
+
+func parseAmounts(amounts []string) (map[string]uint64, error) {
+	result := make(map[string]uint64)
+	for _, a := range amounts {
+		parts := strings.SplitN(a, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid amount %q (expected CURRENCY=AMOUNT)", a)
+		}
+		amount, err := parseCoins(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		result[strings.ToUpper(parts[0])] = amount
+	}
+	return result, nil
+}
+
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+	var reply string
+	if _, err := fmt.Scanln(&reply); err != nil {
+		return false
+	}
+	reply = strings.ToLower(reply)
+	return reply == "y" || reply == "yes"
+}
+
+var openChannelsCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open lndbtc, lndltc and connext channels with opendex peers",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext()
+		defer cancel()
+
+		amounts, err := parseAmounts(openChannelsOpts.Amounts)
+		if err != nil {
+			return fmt.Errorf("--amount: %w", err)
+		}
+		var currencies []string
+		for _, c := range openChannelsOpts.Currencies {
+			currencies = append(currencies, strings.ToUpper(c))
+		}
+
+		plans, err := launcher.PlanChannels(ctx, core.OpenChannelsOptions{
+			Currencies: currencies,
+			Peer:       openChannelsOpts.Peer,
+			Amounts:    amounts,
+		})
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CURRENCY\tAMOUNT\tPEER\tREMOTE\tACTION")
+		var opening []string
+		for _, p := range plans {
+			action := "open"
+			if p.Skip != "" {
+				action = "skip: " + p.Skip
+			} else {
+				opening = append(opening, p.Currency)
+			}
+			peer := p.Peer
+			if p.PeerAlias != "" {
+				peer = p.PeerAlias
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Currency, formatAmount(p.Amount), peer, p.RemoteId, action)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if len(opening) == 0 {
+			fmt.Println("No channels to open")
+			return nil
+		}
+
+		if !openChannelsOpts.Yes && !confirm("Open these channels?") {
+			return nil
+		}
+
+		if err := launcher.OpenChannels(ctx, plans); err != nil {
+			return err
+		}
+
+		if openChannelsOpts.Wait == 0 {
+			return nil
+		}
+
+		fmt.Printf("Waiting for %s channels to become active...\n", strings.Join(opening, ", "))
+		if err := launcher.WaitChannelsActive(ctx, opening, openChannelsOpts.Wait); err != nil {
+			return err
+		}
+		fmt.Println("Channels are active and their opendexd components are ready")
+
+		r, err := launcher.GetOpendexdReadiness(ctx)
+		if err != nil {
+			return err
+		}
+		if !r.Ready {
+			fmt.Printf("Warning: opendexd is not ready (%s)", r.Status)
+			if len(r.NoChannels) > 0 {
+				fmt.Printf(", no active channels for %s", strings.Join(r.NoChannels, ", "))
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
@@ -12,6 +12,9 @@ var genCmd = &cobra.Command{
 	Use:   "gen",
 	Short: "Generate docker-compose.yml and config.json files",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := launcher.DiscoverExternalIp(); err != nil {
+			return err
+		}
 		return launcher.Apply()
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -1,3 +1,81 @@
 package cmd
 
-// TODO getinfo
+import (
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/tor"
+	"github.com/spf13/cobra"
+	"os"
+	"text/tabwriter"
+)
+
+type GetinfoOptions struct {
+	Json         bool
+	Reachability bool
+}
+
+var (
+	getinfoOpts GetinfoOptions
+)
+
+func init() {
+	getinfoCmd.PersistentFlags().BoolVar(&getinfoOpts.Json, "json", false, "print all information as JSON")
+	getinfoCmd.PersistentFlags().BoolVar(&getinfoOpts.Reachability, "reachability", false, "dial the advertised clearnet addresses to check whether the nodes are reachable")
+	rootCmd.AddCommand(getinfoCmd)
+}
+
+var getinfoCmd = &cobra.Command{
+	Use:   "getinfo",
+	Short: "Show launcher, wallet, backup and node reachability information",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext()
+		defer cancel()
+
+		info := launcher.GetInfo(ctx, getinfoOpts.Reachability)
+
+		if getinfoOpts.Json {
+			return printJson(info)
+		}
+
+		fmt.Printf("Launcher: %s (%s)\n", info.Launcher.Version, info.Launcher.Commit)
+		fmt.Printf("Network: %s (%s)\n", info.Network, info.NetworkDir)
+		if info.Attach.Attached {
+			fmt.Printf("Attached to proxy: since %s (pid %d)\n", info.Attach.Since.Local().Format("2006-01-02 15:04:05"), info.Attach.Pid)
+		} else {
+			fmt.Println("Attached to proxy: no")
+		}
+		fmt.Printf("Wallet: %s\n", info.Wallets.State)
+		fmt.Printf("Default wallet password: %t\n", info.Wallets.DefaultPassword)
+		fmt.Printf("Backup location: %s", info.Backup.Location)
+		if info.Backup.DefaultLocation {
+			fmt.Print(" (default)")
+		}
+		fmt.Println()
+		externalIp := info.ExternalIp
+		if externalIp == "" {
+			externalIp = "none"
+		}
+		fmt.Printf("External IP: %s\n", externalIp)
+		fmt.Println()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tTOR\tADDRESS\tCLEARNET\tREACHABILITY")
+		for _, n := range info.Nodes {
+			mode, address := "unknown", ""
+			if n.Tor != nil {
+				mode, address = string(n.Tor.Mode), n.Tor.OnionAddress
+				if n.Tor.Mode == tor.Proxy {
+					address = "via " + n.Tor.Proxy
+				}
+			}
+			clearnet := n.ExternalAddress
+			if clearnet == "" {
+				clearnet = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", n.Service, mode, address, clearnet, n.Reachability)
+		}
+		return w.Flush()
+	},
+}
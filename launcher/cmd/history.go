@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/core"
+	"github.com/spf13/cobra"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type HistoryOptions struct {
+	Service string
+	Type    string
+	Since   string
+	Json    bool
+}
+
+var (
+	historyOpts HistoryOptions
+)
+
+func init() {
+	historyCmd.PersistentFlags().StringVar(&historyOpts.Service, "service", "", "only show events of this service")
+	var types []string
+	for _, t := range core.EventTypes {
+		types = append(types, string(t))
+	}
+	historyCmd.PersistentFlags().StringVar(&historyOpts.Type, "type", "", fmt.Sprintf("only show events of this type (%s)", strings.Join(types, ", ")))
+	historyCmd.PersistentFlags().StringVar(&historyOpts.Since, "since", "", "only show events newer than this duration (e.g. 30m, 24h, 7d)")
+	historyCmd.PersistentFlags().BoolVar(&historyOpts.Json, "json", false, "print events as JSON")
+	rootCmd.AddCommand(historyCmd)
+}
+
+// parseSince parses a duration which additionally supports the "d" (days) unit
+func parseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration: %s", s)
+		}
+		return time.Now().AddDate(0, 0, -days), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(-d), nil
+}
+
+// parseEventType checks s against the event types, an empty s matches all
+func parseEventType(s string) (core.EventType, error) {
+	if s == "" {
+		return "", nil
+	}
+	for _, t := range core.EventTypes {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown event type: %s", s)
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the event journal of the launcher",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := parseSince(historyOpts.Since)
+		if err != nil {
+			return fmt.Errorf("--since: %w", err)
+		}
+		eventType, err := parseEventType(historyOpts.Type)
+		if err != nil {
+			return fmt.Errorf("--type: %w", err)
+		}
+		events, err := launcher.Journal.Query(core.JournalFilter{
+			Service: historyOpts.Service,
+			Type:    eventType,
+			Since:   since,
+		})
+		if err != nil {
+			return err
+		}
+
+		if historyOpts.Json {
+			if events == nil {
+				events = []core.Event{}
+			}
+			j, err := json.MarshalIndent(events, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(j))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tTYPE\tSERVICE\tOUTCOME\tMESSAGE")
+		for _, e := range events {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Time.Local().Format("2006-01-02 15:04:05"), e.Type, e.Service, e.Outcome, e.Message)
+		}
+		return w.Flush()
+	},
+}
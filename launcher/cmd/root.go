@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker/launcher/core"
+	"github.com/opendexnetwork/opendex-docker/launcher/log"
 	"github.com/spf13/cobra"
 	"math/rand"
 	"os"
@@ -11,14 +12,27 @@ import (
 	"time"
 )
 
+type LogOptions struct {
+	Level      string
+	Format     string
+	MaxSize    int
+	MaxAge     int
+	MaxBackups int
+	Compress   bool
+}
+
 var (
 	rootCmd = &cobra.Command{
 		Use:           "launcher",
 		Short:         fmt.Sprintf("XUD environment launcher"),
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return applyLogOptions()
+		},
 	}
 	launcher *core.Launcher
+	logOpts  LogOptions
 )
 
 func init() {
@@ -34,6 +48,30 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+
+	rootCmd.PersistentFlags().StringVar(&logOpts.Level, "log-level", "debug", "launcher.log level (trace, debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVar(&logOpts.Format, "log-format", "text", "launcher.log format (text, json)")
+	rootCmd.PersistentFlags().IntVar(&logOpts.MaxSize, "log-max-size", log.DefaultMaxSize, "Rotate launcher.log when it grows beyond this size in megabytes (0 to disable)")
+	rootCmd.PersistentFlags().IntVar(&logOpts.MaxAge, "log-max-age", log.DefaultMaxAge, "Rotate launcher.log and remove rotated files older than this many days (0 to disable)")
+	rootCmd.PersistentFlags().IntVar(&logOpts.MaxBackups, "log-max-backups", log.DefaultMaxBackups, "Number of rotated launcher.log files to keep (0 to keep all)")
+	rootCmd.PersistentFlags().BoolVar(&logOpts.Compress, "log-compress", true, "Compress rotated launcher.log files with gzip")
+	rootCmd.PersistentFlags().StringVar(&launcher.ExternalIp, "external-ip", launcher.ExternalIp, "Address advertised to lnd and opendexd peers, \"auto\" to discover it through UPnP, NAT-PMP or STUN when running gen or setup (the p2p ports still have to be forwarded on the router)")
+	rootCmd.PersistentFlags().StringVar(&launcher.MetricsListen, "metrics-listen", "", "Serve Prometheus metrics on this address while setup or monitor runs (e.g. 127.0.0.1:9090)")
+}
+
+func applyLogOptions() error {
+	if err := log.SetLevel(logOpts.Level); err != nil {
+		return fmt.Errorf("--log-level: %w", err)
+	}
+	if err := log.SetFormat(logOpts.Format); err != nil {
+		return fmt.Errorf("--log-format: %w", err)
+	}
+	f := launcher.LogFile
+	f.MaxSize = logOpts.MaxSize
+	f.MaxAge = logOpts.MaxAge
+	f.MaxBackups = logOpts.MaxBackups
+	f.Compress = logOpts.Compress
+	return nil
 }
 
 func Execute() {
@@ -22,6 +22,9 @@ var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Set up OpenDEX environment",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := launcher.DiscoverExternalIp(); err != nil {
+			return err
+		}
 		return launcher.Apply()
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
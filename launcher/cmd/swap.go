@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type SwapOptions struct {
+	Json bool
+	Yes  bool
+}
+
+var (
+	swapOpts SwapOptions
+)
+
+func init() {
+	swapListCmd.PersistentFlags().BoolVar(&swapOpts.Json, "json", false, "print swaps as JSON")
+	swapRefundCmd.PersistentFlags().BoolVar(&swapOpts.Json, "json", false, "print the refund as JSON")
+	swapInCmd.PersistentFlags().BoolVarP(&swapOpts.Yes, "yes", "y", false, "swap without confirmation")
+	swapOutCmd.PersistentFlags().BoolVarP(&swapOpts.Yes, "yes", "y", false, "swap without confirmation")
+	swapCmd.AddCommand(swapInCmd, swapOutCmd, swapRefundCmd, swapListCmd)
+	rootCmd.AddCommand(swapCmd)
+}
+
+var swapCmd = &cobra.Command{
+	Use:   "swap",
+	Short: "Rebalance lndbtc and lndltc channels with boltz submarine swaps",
+}
+
+var swapInCmd = &cobra.Command{
+	Use:   "in CHAIN AMOUNT",
+	Short: "Swap onchain coins of the lnd wallet into the channels (e.g. swap in btc 0.01)",
+	Args:  cobra.ExactArgs(2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chain := strings.ToLower(args[0])
+		amount, err := parseCoins(args[1])
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := newContext()
+		defer cancel()
+
+		fee, err := launcher.GetSwapFee(ctx, chain, amount, false)
+		if err != nil {
+			return err
+		}
+		if !swapOpts.Yes && !confirm(fmt.Sprintf("Swap %s %s into the channels for a fee of %s?", formatAmount(amount), strings.ToUpper(chain), formatAmount(fee))) {
+			return nil
+		}
+
+		output, err := launcher.SwapIn(ctx, chain, amount, fee)
+		if err != nil {
+			return err
+		}
+		fmt.Println(output)
+		return nil
+	},
+}
+
+var swapOutCmd = &cobra.Command{
+	Use:   "out CHAIN AMOUNT [ADDRESS]",
+	Short: "Swap coins out of the channels to ADDRESS or the lnd wallet (e.g. swap out ltc 0.5)",
+	Args:  cobra.RangeArgs(2, 3),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chain := strings.ToLower(args[0])
+		amount, err := parseCoins(args[1])
+		if err != nil {
+			return err
+		}
+		address := ""
+		if len(args) == 3 {
+			address = args[2]
+		}
+		to := "the lnd wallet"
+		if address != "" {
+			to = address
+		}
+
+		ctx, cancel := newContext()
+		defer cancel()
+
+		fee, err := launcher.GetSwapFee(ctx, chain, amount, true)
+		if err != nil {
+			return err
+		}
+		if !swapOpts.Yes && !confirm(fmt.Sprintf("Swap %s %s out of the channels to %s for a fee of %s?", formatAmount(amount), strings.ToUpper(chain), to, formatAmount(fee))) {
+			return nil
+		}
+
+		output, err := launcher.SwapOut(ctx, chain, amount, address, fee)
+		if err != nil {
+			return err
+		}
+		fmt.Println(output)
+		return nil
+	},
+}
+
+var swapRefundCmd = &cobra.Command{
+	Use:   "refund CHAIN ID",
+	Short: "Show the refund of a failed swap, boltz refunds it to the lnd wallet after the timeout",
+	Args:  cobra.ExactArgs(2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext()
+		defer cancel()
+
+		refund, err := launcher.GetSwapRefund(ctx, strings.ToLower(args[0]), args[1])
+		if err != nil {
+			return err
+		}
+
+		if swapOpts.Json {
+			return printJson(refund)
+		}
+
+		s := refund.Swap
+		fmt.Printf("Swap %s: %s (%s)\n", s.Id, s.State, s.Status)
+		switch {
+		case s.RefundTransactionId != "":
+			fmt.Printf("Refunded in transaction %s\n", s.RefundTransactionId)
+		case s.LockupTransactionId == "":
+			fmt.Println("No coins were locked up, there is nothing to refund")
+		case !s.NeedsRefund():
+			fmt.Println("The swap has not failed, there is nothing to refund")
+		case refund.BlocksLeft > 0:
+			fmt.Printf("Refund of lockup transaction %s at block %d (%d blocks left)\n", s.LockupTransactionId, s.TimeoutBlockHeight, refund.BlocksLeft)
+		default:
+			fmt.Printf("Refund of lockup transaction %s is due since block %d\n", s.LockupTransactionId, s.TimeoutBlockHeight)
+		}
+		return nil
+	},
+}
+
+var swapListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the swaps of all chains",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext()
+		defer cancel()
+
+		swaps, err := launcher.ListSwaps(ctx)
+		if err != nil {
+			return err
+		}
+
+		if swapOpts.Json {
+			return printJson(swaps)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHAIN\tTYPE\tID\tSTATE\tSTATUS\tAMOUNT\tTRANSACTION")
+		for _, s := range swaps {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				strings.ToUpper(s.Chain),
+				s.Type,
+				s.Id,
+				s.State,
+				s.Status,
+				formatAmount(uint64(s.Amount)),
+				s.Transaction,
+			)
+		}
+		return w.Flush()
+	},
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"os/exec"
+	"runtime"
+)
+
+type WebuiOpenOptions struct {
+	Print bool
+}
+
+var (
+	webuiOpenOpts WebuiOpenOptions
+)
+
+func init() {
+	webuiOpenCmd.PersistentFlags().BoolVar(&webuiOpenOpts.Print, "print", false, "only print the URL")
+	webuiCmd.AddCommand(webuiOpenCmd)
+	rootCmd.AddCommand(webuiCmd)
+}
+
+var webuiCmd = &cobra.Command{
+	Use:   "webui",
+	Short: "Access the webui",
+}
+
+// openBrowser opens url in the default browser of the desktop
+func openBrowser(url string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		c = exec.Command("xdg-open", url)
+	case "darwin":
+		c = exec.Command("open", url)
+	case "windows":
+		c = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+	return c.Start()
+}
+
+var webuiOpenCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the webui in the browser",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return launcher.Apply()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url, err := launcher.GetWebuiUrl()
+		if err != nil {
+			return err
+		}
+		if webuiOpenOpts.Print {
+			fmt.Println(url)
+			return nil
+		}
+		if err := openBrowser(url); err != nil {
+			// e.g. a headless server
+			fmt.Printf("Failed to open the browser (%s), open this URL instead:\n", err)
+		}
+		fmt.Println(url)
+		return nil
+	},
+}
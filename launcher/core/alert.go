@@ -0,0 +1,345 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/log"
+	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"github.com/opendexnetwork/opendex-docker/launcher/utils"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventStatusChanged EventType = "status"
+	EventServiceStuck  EventType = "stuck"
+	EventBackupFailed  EventType = "backup_failed"
+	EventNoChannels    EventType = "no_channels"
+	EventTest          EventType = "test"
+	EventSetupPhase    EventType = "setup_phase"
+	EventWallet        EventType = "wallet"
+	EventBackupChanged EventType = "backup_location"
+	EventUpdate        EventType = "update"
+	EventRescue        EventType = "rescue"
+	EventChannel       EventType = "channel"
+	EventExternalIp    EventType = "external_ip"
+	EventSwap          EventType = "swap"
+)
+
+// EventTypes lists all event types, keep it in sync with the constants above
+var EventTypes = []EventType{
+	EventStatusChanged,
+	EventServiceStuck,
+	EventBackupFailed,
+	EventNoChannels,
+	EventTest,
+	EventSetupPhase,
+	EventWallet,
+	EventBackupChanged,
+	EventUpdate,
+	EventRescue,
+	EventChannel,
+	EventExternalIp,
+	EventSwap,
+}
+
+const (
+	OutcomeOk     = "ok"
+	OutcomeFailed = "failed"
+
+	DefaultAlertCooldown = 10 * time.Minute
+	// NotifyFlushTimeout bounds how long the launcher waits for pending
+	// notifications before it exits
+	NotifyFlushTimeout = 30 * time.Second
+)
+
+type Event struct {
+	Time    time.Time `json:"time"`
+	Network string    `json:"network"`
+	Type    EventType `json:"type"`
+	Service string    `json:"service,omitempty"`
+	From    string    `json:"from,omitempty"`
+	To      string    `json:"to,omitempty"`
+	Phase   string    `json:"phase,omitempty"`
+	Outcome string    `json:"outcome,omitempty"`
+	Message string    `json:"message"`
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeFailed
+	}
+	return OutcomeOk
+}
+
+func (t Event) Title() string {
+	if t.Service == "" {
+		return string(t.Type)
+	}
+	return fmt.Sprintf("%s %s", t.Service, t.Type)
+}
+
+func (t Event) String() string {
+	return fmt.Sprintf("[%s] %s", t.Network, t.Message)
+}
+
+// AlertRule selects the events which should be sent to notifiers. Service,
+// From and To are glob patterns (e.g. "Waiting for *"), empty matches any.
+type AlertRule struct {
+	Event   EventType `json:"event"`
+	Service string    `json:"service,omitempty"`
+	From    string    `json:"from,omitempty"`
+	To      string    `json:"to,omitempty"`
+	// Notify lists notifier names, empty means all notifiers
+	Notify []string `json:"notify,omitempty"`
+	// Cooldown suppresses repeated alerts of this rule for the same service (e.g. "30m")
+	Cooldown string `json:"cooldown,omitempty"`
+
+	cooldown time.Duration
+}
+
+func matchPattern(pattern string, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
+
+func (t *AlertRule) Match(e Event) bool {
+	return t.Event == e.Type &&
+		matchPattern(t.Service, e.Service) &&
+		matchPattern(t.From, e.From) &&
+		matchPattern(t.To, e.To)
+}
+
+type AlertsConfig struct {
+	Notifiers []NotifierConfig `json:"notifiers"`
+	Rules     []AlertRule      `json:"rules"`
+}
+
+type Alerts struct {
+	Rules     []AlertRule
+	Notifiers map[string]Notifier
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// loadAlerts reads alerts.json in the network directory. Alerting is disabled
+// when the file doesn't exist. SMTP passwords are moved from the file into
+// store.
+func loadAlerts(file string, store types.SecretStore) (*Alerts, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var c AlertsConfig
+	err = json.NewDecoder(f).Decode(&c)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+
+	if err := loadSmtpPasswords(file, &c, store); err != nil {
+		return nil, err
+	}
+
+	a := Alerts{
+		Notifiers: make(map[string]Notifier),
+		lastSent:  make(map[string]time.Time),
+	}
+
+	for i, nc := range c.Notifiers {
+		name := notifierName(nc, i)
+		n, err := newNotifier(nc)
+		if err != nil {
+			return nil, fmt.Errorf("notifier %s: %w", name, err)
+		}
+		a.Notifiers[name] = n
+	}
+
+	for i, r := range c.Rules {
+		for _, name := range r.Notify {
+			if _, ok := a.Notifiers[name]; !ok {
+				return nil, fmt.Errorf("rule %d: notifier not found: %s", i, name)
+			}
+		}
+		r.cooldown = DefaultAlertCooldown
+		if r.Cooldown != "" {
+			r.cooldown, err = time.ParseDuration(r.Cooldown)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: invalid cooldown: %w", i, err)
+			}
+		}
+		a.Rules = append(a.Rules, r)
+	}
+
+	return &a, nil
+}
+
+// notifiers returns the notifiers which should receive e
+func (t *Alerts) notifiers(e Event) map[string]Notifier {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make(map[string]Notifier)
+	for i, r := range t.Rules {
+		if !r.Match(e) {
+			continue
+		}
+		key := fmt.Sprintf("%d/%s", i, e.Service)
+		if last, ok := t.lastSent[key]; ok && e.Time.Sub(last) < r.cooldown {
+			continue
+		}
+		t.lastSent[key] = e.Time
+		if len(r.Notify) == 0 {
+			for name, n := range t.Notifiers {
+				result[name] = n
+			}
+		} else {
+			for _, name := range r.Notify {
+				result[name] = t.Notifiers[name]
+			}
+		}
+	}
+	return result
+}
+
+func (t *Launcher) notify(ctx context.Context, notifiers map[string]Notifier, e Event) error {
+	var wg sync.WaitGroup
+	errs := make(chan error, len(notifiers))
+	for name, n := range notifiers {
+		wg.Add(1)
+		go func(name string, n Notifier) {
+			defer wg.Done()
+			if err := n.Notify(ctx, e); err != nil {
+				t.Logger.Errorf("[alert] Failed to notify %s: %s", name, err)
+				errs <- fmt.Errorf("%s: %w", name, err)
+			}
+		}(name, n)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// emit records an event of the launcher into the journal and sends alerts for it
+func (t *Launcher) emit(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Network = string(t.Network)
+
+	t.Logger.Debugf("[event] %s: %s", e.Title(), e.Message)
+
+	if err := t.Journal.Append(e); err != nil {
+		t.Logger.Errorf("Failed to append event to journal: %s", err)
+	}
+
+	if t.Alerts == nil {
+		return
+	}
+	notifiers := t.Alerts.notifiers(e)
+	if len(notifiers) == 0 {
+		return
+	}
+	// Close waits for the delivery, short-lived commands would exit before it
+	t.notifications.Add(1)
+	go func() {
+		defer t.notifications.Done()
+		_ = t.notify(context.Background(), notifiers, e)
+	}()
+}
+
+// flushNotifications waits up to timeout for the pending notifications
+func (t *Launcher) flushNotifications(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		t.notifications.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Logger.Warnf("[alert] Gave up waiting for notifications after %s", timeout)
+	}
+}
+
+// TestAlerts sends a test event to all configured notifiers
+func (t *Launcher) TestAlerts(ctx context.Context) error {
+	if t.alertsErr != nil {
+		return t.alertsErr
+	}
+	if t.Alerts == nil {
+		return fmt.Errorf("no alerts configured in %s", filepath.Join(t.NetworkDir, "alerts.json"))
+	}
+	e := Event{
+		Time:    time.Now(),
+		Network: string(t.Network),
+		Type:    EventTest,
+		Message: "This is a test alert from the OpenDEX launcher",
+	}
+	return t.notify(ctx, t.Alerts.Notifiers, e)
+}
+
+func notifierName(c NotifierConfig, i int) string {
+	if c.Name == "" {
+		return fmt.Sprintf("%s-%d", c.Type, i)
+	}
+	return c.Name
+}
+
+// loadSmtpPasswords stores the SMTP passwords given in file encrypted and
+// rewrites the file without them, otherwise it reads the stored ones
+func loadSmtpPasswords(file string, c *AlertsConfig, store types.SecretStore) error {
+	passwords := make([]string, len(c.Notifiers))
+	moved := false
+	for i, nc := range c.Notifiers {
+		if nc.Type != "smtp" {
+			continue
+		}
+		name := fmt.Sprintf("alerts.%s-smtp-password", notifierName(nc, i))
+		if nc.Password != "" {
+			if err := store.Put(name, nc.Password); err != nil {
+				return fmt.Errorf("store %s: %w", name, err)
+			}
+			passwords[i] = nc.Password
+			c.Notifiers[i].Password = ""
+			moved = true
+			continue
+		}
+		password, err := store.Get(name)
+		if err != nil {
+			return fmt.Errorf("load %s: %w", name, err)
+		}
+		passwords[i] = password
+	}
+
+	if moved {
+		data, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := utils.WriteFileSecure(file, append(data, '\n')); err != nil {
+			return fmt.Errorf("remove passwords from %s: %w", file, err)
+		}
+	}
+
+	for i, password := range passwords {
+		if password != "" {
+			log.RegisterSecret(password)
+			c.Notifiers[i].Password = password
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	dt "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	docker "github.com/docker/docker/client"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/arby"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ArbyConfig is arby.json in the network directory. It declares the arby
+// instances trading other pairs next to the default arby, e.g.
+// {"instances": ["eth-btc", "ltc-btc"]}. An instance is configured with the
+// flags of its service, e.g. --arby-eth-btc.margin.
+type ArbyConfig struct {
+	Instances []string `json:"instances"`
+}
+
+// arbyStopTimeout is how long a dropped arby instance gets to shut down
+const arbyStopTimeout = 30 * time.Second
+
+var reArbyInstance = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// loadArbyInstances returns the instance names declared in arby.json
+func loadArbyInstances(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var c ArbyConfig
+	if err := json.NewDecoder(f).Decode(&c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+
+	var instances []string
+	seen := make(map[string]bool)
+	for _, instance := range c.Instances {
+		instance = strings.TrimPrefix(strings.TrimSpace(instance), "arby-")
+		if !reArbyInstance.MatchString(instance) {
+			return nil, fmt.Errorf("%s: invalid instance name: %q", file, instance)
+		}
+		if seen[instance] {
+			continue
+		}
+		seen[instance] = true
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// getArbyServices returns the names of the default arby and the arby
+// instances
+func (t *Launcher) getArbyServices() []string {
+	var names []string
+	for _, name := range t.ServicesOrder {
+		if _, ok := t.Services[name].(*arby.Service); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// removeArbyOrphans stops and removes the containers of the arby instances
+// which are no longer declared in arby.json
+func (t *Launcher) removeArbyOrphans(ctx context.Context) error {
+	client, err := docker.NewClientWithOpts(docker.FromEnv)
+	if err != nil {
+		return fmt.Errorf("create docker client: %w", err)
+	}
+	defer client.Close()
+
+	containers, err := client.ContainerList(ctx, dt.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+string(t.Network))),
+	})
+	if err != nil {
+		return fmt.Errorf("list containers: %w", err)
+	}
+	for _, c := range containers {
+		name := c.Labels["com.docker.compose.service"]
+		if !strings.HasPrefix(name, "arby-") {
+			continue
+		}
+		if _, ok := t.Services[name]; ok {
+			continue
+		}
+		t.Logger.Infof("Removing %s which is no longer declared in arby.json", name)
+		timeout := arbyStopTimeout
+		if err := client.ContainerStop(ctx, c.ID, &timeout); err != nil {
+			return fmt.Errorf("stop %s: %w", name, err)
+		}
+		if err := client.ContainerRemove(ctx, c.ID, dt.ContainerRemoveOptions{}); err != nil {
+			return fmt.Errorf("remove %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateArby checks the configuration of the enabled arby services
+func (t *Launcher) validateArby() error {
+	for _, name := range t.getArbyServices() {
+		s := t.Services[name].(*arby.Service)
+		if s.IsDisabled() {
+			continue
+		}
+		if err := s.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetArbyReport collects the trading report of the arby service name with
+// the latest limit (0 for all) trades
+func (t *Launcher) GetArbyReport(ctx context.Context, name string, limit int) (*arby.Report, error) {
+	s, ok := t.runningService(name)
+	if !ok {
+		return nil, fmt.Errorf("%s is not running", name)
+	}
+	a, ok := s.(*arby.Service)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an arby service", name)
+	}
+	var report *arby.Report
+	err := t.inNetworkDir(func() error {
+		var err error
+		report, err = a.GetReport(ctx, limit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -1,5 +1,76 @@
 package core
 
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
 func (t *Launcher) Attach() error {
 	return nil
 }
+
+// AttachInfo tells whether a launcher is attached to the proxy and serves its
+// requests
+type AttachInfo struct {
+	Attached bool       `json:"attached"`
+	Pid      int        `json:"pid,omitempty"`
+	Url      string     `json:"url,omitempty"`
+	Since    *time.Time `json:"since,omitempty"`
+}
+
+func (t *Launcher) attachFile() string {
+	return filepath.Join(t.NetworkDir, "attached.json")
+}
+
+// markAttached records the attachment for "getinfo" of other launcher
+// processes
+func (t *Launcher) markAttached(url string) {
+	now := time.Now().UTC()
+	data, err := json.Marshal(AttachInfo{Attached: true, Pid: os.Getpid(), Url: url, Since: &now})
+	if err == nil {
+		err = ioutil.WriteFile(t.attachFile(), data, 0644)
+	}
+	if err != nil {
+		t.Logger.Warnf("Failed to record the attachment: %s", err)
+	}
+}
+
+func (t *Launcher) markDetached() {
+	if err := os.Remove(t.attachFile()); err != nil && !os.IsNotExist(err) {
+		t.Logger.Warnf("Failed to remove the attachment record: %s", err)
+	}
+}
+
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		// FindProcess fails for exited processes on Windows
+		return true
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+func (t *Launcher) getAttachInfo() AttachInfo {
+	data, err := ioutil.ReadFile(t.attachFile())
+	if err != nil {
+		return AttachInfo{}
+	}
+	var info AttachInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Logger.Debugf("Failed to parse %s: %s", t.attachFile(), err)
+		return AttachInfo{}
+	}
+	// a launcher which was killed leaves the record behind
+	if !processAlive(info.Pid) {
+		return AttachInfo{}
+	}
+	return info
+}
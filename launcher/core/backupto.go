@@ -10,6 +10,27 @@ import (
 )
 
 func (t *Launcher) BackupTo(ctx context.Context, location string) error {
+	if err := t.backupTo(ctx, location); err != nil {
+		t.emit(Event{
+			Type:    EventBackupFailed,
+			Service: "opendexd",
+			To:      location,
+			Outcome: OutcomeFailed,
+			Message: fmt.Sprintf("Failed to change backup location to %s: %s", location, err),
+		})
+		return err
+	}
+	t.emit(Event{
+		Type:    EventBackupChanged,
+		Service: "opendexd",
+		To:      location,
+		Outcome: OutcomeOk,
+		Message: fmt.Sprintf("Changed backup location to %s", location),
+	})
+	return nil
+}
+
+func (t *Launcher) backupTo(ctx context.Context, location string) error {
 	t.BackupDir = location
 	if err := t.Apply(); err != nil {
 		return err
@@ -0,0 +1,259 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/connext"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/lnd"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/opendexd"
+	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"math/big"
+	"sort"
+)
+
+// CurrencyBalance amounts are in satoshis (1e-8 of a coin) for all
+// currencies like opendexd reports them
+type CurrencyBalance struct {
+	Currency                 string `json:"currency"`
+	WalletBalance            uint64 `json:"walletBalance"`
+	UnconfirmedWalletBalance uint64 `json:"unconfirmedWalletBalance"`
+	ChannelCapacity          uint64 `json:"channelCapacity"`
+	LocalBalance             uint64 `json:"localBalance"`
+	RemoteBalance            uint64 `json:"remoteBalance"`
+	PendingChannels          int    `json:"pendingChannels"`
+	PendingLocalBalance      uint64 `json:"pendingLocalBalance"`
+	MaxSell                  uint64 `json:"maxSell"`
+	MaxBuy                   uint64 `json:"maxBuy"`
+}
+
+const (
+	ChannelActive   = "active"
+	ChannelInactive = "inactive"
+	ChannelPending  = "pending"
+)
+
+type ChannelInfo struct {
+	Currency      string `json:"currency"`
+	Service       string `json:"service"`
+	Id            string `json:"id"`
+	Peer          string `json:"peer"`
+	State         string `json:"state"`
+	Capacity      uint64 `json:"capacity"`
+	LocalBalance  uint64 `json:"localBalance"`
+	RemoteBalance uint64 `json:"remoteBalance"`
+}
+
+type Overview struct {
+	Balances []*CurrencyBalance `json:"balances"`
+	Channels []ChannelInfo      `json:"channels"`
+	// Errors maps services to the errors of querying them
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+func (t *Overview) balance(currency string) *CurrencyBalance {
+	for _, b := range t.Balances {
+		if b.Currency == currency {
+			return b
+		}
+	}
+	b := &CurrencyBalance{Currency: currency}
+	t.Balances = append(t.Balances, b)
+	return b
+}
+
+func (t *Overview) addChannel(c ChannelInfo) {
+	t.Channels = append(t.Channels, c)
+	b := t.balance(c.Currency)
+	if c.State == ChannelPending {
+		b.PendingChannels++
+		b.PendingLocalBalance += c.LocalBalance
+		return
+	}
+	b.ChannelCapacity += c.Capacity
+	b.LocalBalance += c.LocalBalance
+	b.RemoteBalance += c.RemoteBalance
+}
+
+// weiToSatoshis converts wei (1e-18 ETH) into the 1e-8 ETH units of opendexd
+func weiToSatoshis(wei *big.Int) uint64 {
+	return new(big.Int).Quo(wei, big.NewInt(1e10)).Uint64()
+}
+
+// runningService returns the service name if it's enabled and running
+func (t *Launcher) runningService(name string) (types.Service, bool) {
+	s, ok := t.Services[name]
+	if !ok || s.IsDisabled() || !s.IsRunning() {
+		return nil, false
+	}
+	return s, true
+}
+
+func (t *Launcher) collectLnd(ctx context.Context, overview *Overview, name string, currency string) error {
+	s, ok := t.runningService(name)
+	if !ok {
+		return nil
+	}
+	l := s.(*lnd.Service)
+
+	wallet, err := l.GetWalletBalance(ctx)
+	if err != nil {
+		return fmt.Errorf("get wallet balance: %w", err)
+	}
+	b := overview.balance(currency)
+	b.WalletBalance = uint64(wallet.ConfirmedBalance)
+	b.UnconfirmedWalletBalance = uint64(wallet.UnconfirmedBalance)
+
+	channels, err := l.ListChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("list channels: %w", err)
+	}
+	for _, c := range channels {
+		state := ChannelActive
+		if !c.Active {
+			state = ChannelInactive
+		}
+		overview.addChannel(ChannelInfo{
+			Currency:      currency,
+			Service:       name,
+			Id:            c.ChannelPoint,
+			Peer:          c.RemotePubkey,
+			State:         state,
+			Capacity:      uint64(c.Capacity),
+			LocalBalance:  uint64(c.LocalBalance),
+			RemoteBalance: uint64(c.RemoteBalance),
+		})
+	}
+
+	pending, err := l.ListPendingOpenChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("list pending channels: %w", err)
+	}
+	for _, c := range pending {
+		overview.addChannel(ChannelInfo{
+			Currency:      currency,
+			Service:       name,
+			Id:            c.ChannelPoint,
+			Peer:          c.RemoteNodePub,
+			State:         ChannelPending,
+			Capacity:      uint64(c.Capacity),
+			LocalBalance:  uint64(c.LocalBalance),
+			RemoteBalance: uint64(c.RemoteBalance),
+		})
+	}
+	return nil
+}
+
+// collectConnext adds the ETH channels of connext. Token channels are left
+// to opendexd which knows the token units.
+func (t *Launcher) collectConnext(ctx context.Context, overview *Overview) error {
+	s, ok := t.runningService("connext")
+	if !ok {
+		return nil
+	}
+	c := s.(*connext.Service)
+	if !c.UseVector() {
+		return nil
+	}
+
+	node, err := c.GetNodeConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("get config: %w", err)
+	}
+	channels, err := c.GetChannels(ctx, node)
+	if err != nil {
+		return fmt.Errorf("get channels: %w", err)
+	}
+	for _, ch := range channels {
+		local, remote := ch.BalanceOf(connext.EthAssetId, node.SignerAddress)
+		overview.addChannel(ChannelInfo{
+			Currency:      "ETH",
+			Service:       "connext",
+			Id:            ch.ChannelAddress,
+			Peer:          ch.Counterparty(node),
+			State:         ChannelActive,
+			Capacity:      weiToSatoshis(new(big.Int).Add(local, remote)),
+			LocalBalance:  weiToSatoshis(local),
+			RemoteBalance: weiToSatoshis(remote),
+		})
+	}
+	return nil
+}
+
+// collectOpendexd fills in the balances of the currencies without channel
+// details (e.g. tokens) and the trading limits
+func (t *Launcher) collectOpendexd(ctx context.Context, overview *Overview) error {
+	s, ok := t.runningService("opendexd")
+	if !ok {
+		return nil
+	}
+	o := s.(*opendexd.Service)
+
+	balances, err := o.GetBalance(ctx)
+	if err != nil {
+		return fmt.Errorf("get balance: %w", err)
+	}
+	for currency, ob := range balances {
+		b := overview.balance(currency)
+		if b.WalletBalance == 0 && b.UnconfirmedWalletBalance == 0 {
+			b.WalletBalance = ob.WalletBalance
+			b.UnconfirmedWalletBalance = ob.UnconfirmedWalletBalance
+		}
+		if b.ChannelCapacity == 0 && b.PendingChannels == 0 {
+			b.LocalBalance = ob.ChannelBalance + ob.InactiveChannelBalance
+			b.PendingLocalBalance = ob.PendingChannelBalance
+		}
+	}
+
+	limits, err := o.GetTradingLimits(ctx)
+	if err != nil {
+		return fmt.Errorf("get trading limits: %w", err)
+	}
+	for currency, l := range limits {
+		b := overview.balance(currency)
+		b.MaxSell = l.MaxSell
+		b.MaxBuy = l.MaxBuy
+	}
+	return nil
+}
+
+// GetOverview gathers the balances and channels of lndbtc, lndltc, connext
+// and opendexd. Services which are not running are skipped and services
+// which fail are reported in Errors.
+func (t *Launcher) GetOverview(ctx context.Context) (*Overview, error) {
+	overview := Overview{
+		Balances: []*CurrencyBalance{},
+		Channels: []ChannelInfo{},
+		Errors:   make(map[string]string),
+	}
+
+	collectors := []struct {
+		name    string
+		collect func() error
+	}{
+		{"lndbtc", func() error { return t.collectLnd(ctx, &overview, "lndbtc", "BTC") }},
+		{"lndltc", func() error { return t.collectLnd(ctx, &overview, "lndltc", "LTC") }},
+		{"connext", func() error { return t.collectConnext(ctx, &overview) }},
+		{"opendexd", func() error { return t.collectOpendexd(ctx, &overview) }},
+	}
+	err := t.inNetworkDir(func() error {
+		for _, c := range collectors {
+			if err := c.collect(); err != nil {
+				if ctx.Err() != nil {
+					return errInterrupted
+				}
+				t.Logger.Debugf("Failed to collect %s balances: %s", c.name, err)
+				overview.Errors[c.name] = err.Error()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(overview.Balances, func(i, j int) bool {
+		return overview.Balances[i].Currency < overview.Balances[j].Currency
+	})
+
+	return &overview, nil
+}
@@ -0,0 +1,317 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/opendexd"
+	"os"
+	"time"
+)
+
+// ChannelCurrencies are the currencies opendexd trades through channels
+var ChannelCurrencies = []string{"BTC", "LTC", "ETH"}
+
+// ChannelLimits constrain the channels of a currency (in satoshis)
+type ChannelLimits struct {
+	// FeeReserve is kept in the wallet for on-chain fees
+	FeeReserve uint64
+	// MinAmount is the smallest channel the node accepts
+	MinAmount uint64
+}
+
+// channelLimits are the lnd limits by currency. connext has neither a
+// minimum deposit nor a reserve in satoshis, so ETH has no limits.
+var channelLimits = map[string]ChannelLimits{
+	"BTC": {FeeReserve: 100000, MinAmount: 20000},
+	"LTC": {FeeReserve: 100000, MinAmount: 20000},
+}
+
+// ChannelPlan describes a channel which "channels open" is going to open
+type ChannelPlan struct {
+	Currency string `json:"currency"`
+	// Amount is in satoshis (1e-8 of a coin)
+	Amount uint64 `json:"amount"`
+	// Peer is the opendex node pubkey of the counterparty
+	Peer      string `json:"peer"`
+	PeerAlias string `json:"peerAlias"`
+	// RemoteId is the lnd pubkey or connext identifier of the counterparty
+	RemoteId string `json:"remoteId"`
+	// Skip is the reason why the channel won't be opened
+	Skip string `json:"skip,omitempty"`
+}
+
+type OpenChannelsOptions struct {
+	Currencies []string
+	// Peer selects the opendex peer by pubkey or alias, the first peer
+	// supporting the currency is used otherwise
+	Peer string
+	// Amounts override the suggested amounts by currency (in satoshis)
+	Amounts map[string]uint64
+}
+
+// SuggestChannelAmount suggests half of the wallet balance left after the fee
+// reserve of currency
+func SuggestChannelAmount(currency string, walletBalance uint64) uint64 {
+	reserve := channelLimits[currency].FeeReserve
+	if walletBalance <= reserve {
+		return 0
+	}
+	return (walletBalance - reserve) / 2
+}
+
+// inNetworkDir runs f in the network directory where docker-compose finds the
+// service containers
+func (t *Launcher) inNetworkDir(f func() error) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.Chdir(t.NetworkDir); err != nil {
+		return fmt.Errorf("change directory: %w", err)
+	}
+
+	return f()
+}
+
+func (t *Launcher) getOpendexd() (*opendexd.Service, error) {
+	s, ok := t.runningService("opendexd")
+	if !ok {
+		return nil, errors.New("opendexd is not running")
+	}
+	return s.(*opendexd.Service), nil
+}
+
+func findPeer(peers []opendexd.Peer, identifier string, currency string) *opendexd.Peer {
+	for i, p := range peers {
+		if identifier != "" && p.NodePubKey != identifier && p.Alias != identifier {
+			continue
+		}
+		if currency == "ETH" {
+			if p.ConnextIdentifier != "" {
+				return &peers[i]
+			}
+		} else if p.LndPubKeys[currency] != "" {
+			return &peers[i]
+		}
+	}
+	return nil
+}
+
+// PlanChannels looks up the peers and wallet balances and suggests a channel
+// for every currency which doesn't have one yet
+func (t *Launcher) PlanChannels(ctx context.Context, opts OpenChannelsOptions) ([]ChannelPlan, error) {
+	var plans []ChannelPlan
+	err := t.inNetworkDir(func() error {
+		o, err := t.getOpendexd()
+		if err != nil {
+			return err
+		}
+		peers, err := o.ListPeers(ctx)
+		if err != nil {
+			return fmt.Errorf("list peers: %w", err)
+		}
+		overview, err := t.GetOverview(ctx)
+		if err != nil {
+			return err
+		}
+		for name, e := range overview.Errors {
+			t.Logger.Warnf("Failed to query %s: %s", name, e)
+		}
+
+		for _, currency := range opts.Currencies {
+			plan := ChannelPlan{Currency: currency}
+			b := overview.balance(currency)
+
+			peer := findPeer(peers, opts.Peer, currency)
+			if peer != nil {
+				plan.Peer = peer.NodePubKey
+				plan.PeerAlias = peer.Alias
+				if currency == "ETH" {
+					plan.RemoteId = peer.ConnextIdentifier
+				} else {
+					plan.RemoteId = peer.LndPubKeys[currency]
+				}
+			}
+
+			if amount, ok := opts.Amounts[currency]; ok {
+				plan.Amount = amount
+			} else {
+				plan.Amount = SuggestChannelAmount(currency, b.WalletBalance)
+			}
+
+			switch {
+			case b.ChannelCapacity > 0 || b.PendingChannels > 0:
+				plan.Skip = "channel exists"
+			case peer == nil:
+				plan.Skip = "no peer supports it"
+			case plan.Amount == 0 || plan.Amount < channelLimits[currency].MinAmount:
+				plan.Skip = "insufficient wallet balance"
+			case plan.Amount > b.WalletBalance:
+				plan.Skip = "amount exceeds wallet balance"
+			}
+
+			plans = append(plans, plan)
+		}
+		return nil
+	})
+	return plans, err
+}
+
+// formatCoins formats satoshis in coin units as opendex-cli expects
+func formatCoins(satoshis uint64) string {
+	return fmt.Sprintf("%d.%08d", satoshis/1e8, satoshis%1e8)
+}
+
+// OpenChannels opens the planned channels through opendexd
+func (t *Launcher) OpenChannels(ctx context.Context, plans []ChannelPlan) error {
+	return t.inNetworkDir(func() error {
+		o, err := t.getOpendexd()
+		if err != nil {
+			return err
+		}
+		var failed []string
+		for _, p := range plans {
+			if p.Skip != "" {
+				continue
+			}
+			output, err := o.OpenChannel(ctx, p.Currency, formatCoins(p.Amount), p.Peer)
+			e := Event{
+				Type:    EventChannel,
+				Service: "opendexd",
+				Outcome: outcome(err),
+				Message: fmt.Sprintf("Opened %s %s channel with %s: %s", formatCoins(p.Amount), p.Currency, p.Peer, output),
+			}
+			if err != nil {
+				e.Message = fmt.Sprintf("Failed to open %s %s channel with %s: %s", formatCoins(p.Amount), p.Currency, p.Peer, err)
+				failed = append(failed, p.Currency)
+			}
+			t.emit(e)
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to open channels: %v", failed)
+		}
+		return nil
+	})
+}
+
+// WaitChannelsActive polls the channels of currencies until they are all
+// active and opendexd is ready, or timeout
+func (t *Launcher) WaitChannelsActive(ctx context.Context, currencies []string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		done, err := t.channelsActive(ctx, currencies)
+		if err != nil {
+			t.Logger.Debugf("Failed to check channels: %s", err)
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("channels are not active after %s", timeout)
+			}
+			return errInterrupted
+		case <-time.After(StatusQueryInterval):
+		}
+	}
+}
+
+// OpendexdReadiness tells whether opendexd is Ready and which currencies keep
+// it waiting for channels
+type OpendexdReadiness struct {
+	Status string
+	Ready  bool
+	// NoChannels are the currencies without an active channel
+	NoChannels []string
+}
+
+// GetOpendexdReadiness checks the overall opendexd status, which only turns
+// Ready when every currency has an active channel
+func (t *Launcher) GetOpendexdReadiness(ctx context.Context) (*OpendexdReadiness, error) {
+	overview, err := t.GetOverview(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[string]bool)
+	for _, c := range overview.Channels {
+		if c.State == ChannelActive {
+			active[c.Currency] = true
+		}
+	}
+
+	var r OpendexdReadiness
+	for _, currency := range ChannelCurrencies {
+		if !active[currency] {
+			r.NoChannels = append(r.NoChannels, currency)
+		}
+	}
+	err = t.inNetworkDir(func() error {
+		o, err := t.getOpendexd()
+		if err != nil {
+			return err
+		}
+		r.Status, err = o.GetStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Ready = isReady(r.Status)
+	return &r, nil
+}
+
+func (t *Launcher) channelsActive(ctx context.Context, currencies []string) (bool, error) {
+	overview, err := t.GetOverview(ctx)
+	if err != nil {
+		return false, err
+	}
+	active := make(map[string]bool)
+	for _, c := range overview.Channels {
+		if c.State == ChannelActive {
+			active[c.Currency] = true
+		}
+	}
+	for _, currency := range currencies {
+		if !active[currency] {
+			t.Logger.Debugf("Waiting for %s channel to be active", currency)
+			return false, nil
+		}
+	}
+
+	// opendexd is only Ready when all of lndbtc, lndltc and connext are, so
+	// just the components of the opened currencies are checked
+	var info *opendexd.Info
+	err = t.inNetworkDir(func() error {
+		o, err := t.getOpendexd()
+		if err != nil {
+			return err
+		}
+		info, err = o.GetInfo(ctx)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, currency := range currencies {
+		var status string
+		switch currency {
+		case "BTC":
+			status = info.Lndbtc.Status
+		case "LTC":
+			status = info.Lndltc.Status
+		default:
+			status = info.Connext.Status
+		}
+		if status != "Ready" {
+			t.Logger.Debugf("Waiting for opendexd %s: %s", currency, status)
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -63,6 +63,20 @@ func (t *Launcher) Restart(ctx context.Context) error {
 }
 
 func (t *Launcher) Stop(ctx context.Context) error {
+	// arby trades through opendexd
+	if err := t.removeArbyOrphans(ctx); err != nil {
+		return err
+	}
+	for _, name := range t.getArbyServices() {
+		s := t.Services[name]
+		if s.IsDisabled() || !s.IsRunning() {
+			continue
+		}
+		if err := t.stopService(ctx, name); err != nil {
+			return err
+		}
+	}
+
 	if t.Network != types.Simnet {
 		if err := t.stopService(ctx, "boltz"); err != nil {
 			return err
@@ -87,6 +101,13 @@ func (t *Launcher) Stop(ctx context.Context) error {
 		return err
 	}
 
+	// webui talks to the proxy
+	if s := t.Services["webui"]; !s.IsDisabled() && s.IsRunning() {
+		if err := t.stopService(ctx, "webui"); err != nil {
+			return err
+		}
+	}
+
 	if err := t.stopService(ctx, "proxy"); err != nil {
 		return err
 	}
@@ -110,7 +131,8 @@ func (t *Launcher) Down(ctx context.Context) error {
 			return err
 		}
 	}
-	c := exec.Command("docker-compose", "down")
+	// the orphans are dropped arby instances
+	c := exec.Command("docker-compose", "down", "--remove-orphans")
 	return utils.Run(ctx, c)
 }
 
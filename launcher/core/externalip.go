@@ -0,0 +1,158 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/nat"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ExternalIpAuto discovers the external IP through UPnP, NAT-PMP or STUN.
+	// It only discovers the address, the p2p ports have to be forwarded on
+	// the router.
+	ExternalIpAuto = "auto"
+
+	// externalIpMaxAge is how long a discovered external IP is reused before
+	// discovering it again
+	externalIpMaxAge        = 1 * time.Hour
+	externalIpTimeout       = 10 * time.Second
+	reachabilityTimeout     = 3 * time.Second
+	ReachabilityReachable   = "reachable"
+	ReachabilityUnreachable = "unreachable"
+	ReachabilityUnknown     = "unknown"
+)
+
+var reHostname = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+
+func (t *Launcher) externalIpCacheFile() string {
+	return filepath.Join(t.NetworkDir, "external-ip")
+}
+
+// resolveExternalIp validates the --external-ip option. When it is "auto" the
+// address discovered by the last gen or setup is used.
+func (t *Launcher) resolveExternalIp() error {
+	value := strings.TrimSpace(t.ExternalIp)
+	switch {
+	case value == "":
+		t.externalIp = ""
+	case value == ExternalIpAuto:
+		// only DiscoverExternalIp does network I/O
+		b, err := ioutil.ReadFile(t.externalIpCacheFile())
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("read external IP: %w", err)
+		}
+		t.externalIp = strings.TrimSpace(string(b))
+		if t.externalIp == "" {
+			t.Logger.Warnf("No external IP was discovered yet, run gen or setup to discover it")
+		}
+	case net.ParseIP(value) != nil:
+		if strings.Contains(value, ":") {
+			return fmt.Errorf("--external-ip: IPv6 addresses are not supported: %s", value)
+		}
+		t.externalIp = value
+	case reHostname.MatchString(value):
+		t.externalIp = value
+	default:
+		return fmt.Errorf("--external-ip: invalid address: %s", value)
+	}
+	return nil
+}
+
+// DiscoverExternalIp refreshes the discovered external IP when --external-ip
+// is "auto". It is called by gen and setup before Apply, so that the other
+// commands don't talk to the gateway or STUN servers.
+func (t *Launcher) DiscoverExternalIp() error {
+	if strings.TrimSpace(t.ExternalIp) != ExternalIpAuto {
+		return nil
+	}
+	_, err := t.discoverExternalIp()
+	return err
+}
+
+// discoverExternalIp reuses the recently discovered IP from the network
+// directory, otherwise it asks the LAN gateway (UPnP, NAT-PMP) or a STUN
+// server. The previously discovered IP is used when the discovery fails.
+func (t *Launcher) discoverExternalIp() (string, error) {
+	f := t.externalIpCacheFile()
+
+	var cached string
+	if b, err := ioutil.ReadFile(f); err == nil {
+		cached = strings.TrimSpace(string(b))
+		if info, err := os.Stat(f); err == nil && time.Since(info.ModTime()) < externalIpMaxAge && cached != "" {
+			return cached, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), externalIpTimeout)
+	defer cancel()
+
+	ip, method, err := nat.Discover(ctx)
+	if err != nil {
+		if cached != "" {
+			t.Logger.Warnf("Failed to discover external IP, using previous %s: %s", cached, err)
+			return cached, nil
+		}
+		return "", fmt.Errorf("discover external IP: %w", err)
+	}
+
+	t.Logger.Debugf("Discovered external IP %s through %s", ip, method)
+	if ip.String() != cached {
+		// no port mappings are requested from the gateway
+		t.Logger.Warnf("Discovered external IP %s, forward the p2p ports %s to this host unless it is reachable directly", ip, t.p2pPorts())
+		t.emit(Event{Type: EventExternalIp, Outcome: OutcomeOk, Message: fmt.Sprintf("External IP %s (%s)", ip, method)})
+	}
+	if err := ioutil.WriteFile(f, []byte(ip.String()+"\n"), 0644); err != nil {
+		t.Logger.Warnf("Failed to save external IP: %s", err)
+	}
+	return ip.String(), nil
+}
+
+// p2pPorts lists the ports peers connect to
+func (t *Launcher) p2pPorts() string {
+	var ports []string
+	for _, name := range t.ServicesOrder {
+		if n, ok := t.Services[name].(p2pNode); ok {
+			ports = append(ports, fmt.Sprintf("%d (%s)", n.GetP2pPort(), name))
+		}
+	}
+	return strings.Join(ports, ", ")
+}
+
+// checkReachable dials the advertised address of a node. A node behind a NAT
+// without hairpinning may be reported unreachable from inside the LAN.
+func checkReachable(ctx context.Context, address string) string {
+	d := net.Dialer{Timeout: reachabilityTimeout}
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return ReachabilityUnreachable
+	}
+	_ = conn.Close()
+	return ReachabilityReachable
+}
+
+// checkNodesReachable fills the reachability of the nodes advertising the
+// external IP concurrently
+func checkNodesReachable(ctx context.Context, nodes []NodeInfo) {
+	var wg sync.WaitGroup
+	for i := range nodes {
+		n := &nodes[i]
+		if n.ExternalAddress == "" {
+			n.Reachability = ReachabilityUnknown
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.Reachability = checkReachable(ctx, n.ExternalAddress)
+		}()
+	}
+	wg.Wait()
+}
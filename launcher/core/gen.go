@@ -4,10 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/utils"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 )
 
+// escapeDollar prevents docker-compose from interpolating variables in values
+func escapeDollar(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
+
 func (t *Launcher) exportDockerComposeYaml() (string, error) {
 	var b strings.Builder
 	b.WriteString("version: \"2.4\"\n")
@@ -28,7 +36,7 @@ func (t *Launcher) exportDockerComposeYaml() (string, error) {
 		if len(s.GetCommand()) > 0 {
 			b.WriteString("    command: >\n")
 			for _, arg := range s.GetCommand() {
-				b.WriteString(fmt.Sprintf("      %s\n", arg))
+				b.WriteString(fmt.Sprintf("      %s\n", escapeDollar(arg)))
 			}
 		}
 		if len(s.GetEnvironment()) > 0 {
@@ -42,10 +50,29 @@ func (t *Launcher) exportDockerComposeYaml() (string, error) {
 						b.WriteString(fmt.Sprintf("        %s\n", line))
 					}
 				} else {
-					b.WriteString(fmt.Sprintf("      - %s=%s\n", k, v))
+					b.WriteString(fmt.Sprintf("      - %s=%s\n", k, escapeDollar(v)))
 				}
 			}
 		}
+		if env := s.GetRuntimeEnvironment(); len(env) > 0 {
+			if len(s.GetEnvironment()) == 0 {
+				b.WriteString("    environment:\n")
+			}
+			var keys []string
+			for k := range env {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			// docker-compose takes the values from its own environment, so
+			// only Create and Up of the launcher pass them
+			for _, k := range keys {
+				b.WriteString(fmt.Sprintf("      - %s\n", k))
+			}
+		}
+		if len(s.GetSecretEnvironment()) > 0 {
+			b.WriteString("    env_file:\n")
+			b.WriteString(fmt.Sprintf("      - %s\n", t.secretEnvFileRelPath(name)))
+		}
 		if len(s.GetVolumes()) > 0 {
 			b.WriteString("    volumes:\n")
 			for _, v := range s.GetVolumes() {
@@ -62,6 +89,50 @@ func (t *Launcher) exportDockerComposeYaml() (string, error) {
 	return b.String(), nil
 }
 
+func (t *Launcher) secretEnvFileRelPath(name string) string {
+	return fmt.Sprintf("./secrets/%s.env", name)
+}
+
+// genSecretEnvFiles writes secret environment variables of services into
+// secrets/<service>.env files which are only readable by the current user
+func (t *Launcher) genSecretEnvFiles() error {
+	dir := filepath.Join(t.NetworkDir, "secrets")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for _, name := range t.ServicesOrder {
+		s := t.Services[name]
+		file := filepath.Join(t.NetworkDir, t.secretEnvFileRelPath(name))
+		env := s.GetSecretEnvironment()
+		if s.IsDisabled() || len(env) == 0 {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+
+		var keys []string
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		for _, k := range keys {
+			v := env[k]
+			if strings.Contains(v, "\n") {
+				return fmt.Errorf("%s: secret %s should not contain newlines", name, k)
+			}
+			b.WriteString(fmt.Sprintf("%s=%s\n", k, v))
+		}
+
+		if err := utils.WriteFileSecure(file, []byte(b.String())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t *Launcher) GenDockerComposeYaml() error {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -72,6 +143,9 @@ func (t *Launcher) GenDockerComposeYaml() error {
 		return err
 	}
 	t.Logger.Debugf("Generate docker-compose.yml in %s", t.NetworkDir)
+	if err := t.genSecretEnvFiles(); err != nil {
+		return fmt.Errorf("generate secret env files: %w", err)
+	}
 	f, err := os.Create("docker-compose.yml")
 	if err != nil {
 		return err
@@ -142,11 +216,15 @@ func (t *Launcher) GenConfigJson() error {
 	}
 	t.Logger.Debugf("Generate config.json in %s", t.DataDir)
 
-	f, err := os.Create("config.json")
+	// config.json contains RPC credentials of external nodes
+	f, err := os.OpenFile("config.json", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
 	content, err := t.exportConfigJson()
 	if err != nil {
 		return err
@@ -1,14 +1,41 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/gorilla/websocket"
+	"github.com/opendexnetwork/opendex-docker/launcher/build"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/bitcoind"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/opendexd"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/tor"
 	"github.com/opendexnetwork/opendex-docker/launcher/utils"
+	"net"
+	"time"
 )
 
+type LauncherInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// ServiceInfo describes an enabled service
+type ServiceInfo struct {
+	Name  string `json:"name"`
+	Mode  string `json:"mode,omitempty"`
+	Image string `json:"image"`
+	// Digest is the digest of the local image, empty when it is not pulled
+	Digest string `json:"digest,omitempty"`
+	// Rpc are the RPC params as exported to config.json without the
+	// credentials
+	Rpc interface{} `json:"rpc"`
+}
+
 type WalletsInfo struct {
-	DefaultPassword bool `json:"defaultPassword"`
-	MnemonicShown   bool `json:"mnemonicShown"`
+	// State is missing, locked, unlocked or unknown
+	State           string `json:"state"`
+	DefaultPassword bool   `json:"defaultPassword"`
+	MnemonicShown   bool   `json:"mnemonicShown"`
 }
 
 type BackupInfo struct {
@@ -16,20 +43,135 @@ type BackupInfo struct {
 	DefaultLocation bool   `json:"defaultLocation"`
 }
 
+// NodeInfo describes how a service is reachable by its peers
+type NodeInfo struct {
+	Service string    `json:"service"`
+	Tor     *tor.Info `json:"tor"`
+	// ExternalAddress is the advertised clearnet address, empty without an
+	// external IP
+	ExternalAddress string `json:"externalAddress"`
+	// Reachability is reachable, unreachable or unknown
+	Reachability string `json:"reachability"`
+}
+
 type Info struct {
-	Wallets WalletsInfo `json:"wallets"`
-	Backup  BackupInfo  `json:"backup"`
+	Launcher   LauncherInfo  `json:"launcher"`
+	Network    string        `json:"network"`
+	NetworkDir string        `json:"networkDir"`
+	Services   []ServiceInfo `json:"services"`
+	Wallets    WalletsInfo   `json:"wallets"`
+	Backup     BackupInfo    `json:"backup"`
+	Attach     AttachInfo    `json:"attach"`
+	ExternalIp string        `json:"externalIp"`
+	Nodes      []NodeInfo    `json:"nodes"`
 }
 
 func (t *Launcher) UsingDefaultPassword() bool {
 	return utils.FileExists(t.PasswordUnsetMarker)
 }
 
-func (t *Launcher) GetInfo() Info {
+type p2pNode interface {
+	GetTorInfo() (*tor.Info, error)
+	GetP2pPort() uint16
+}
+
+// getNodes lists the p2p nodes. Dialing the advertised addresses takes up to
+// reachabilityTimeout, so it is only done when checkReachability is set.
+func (t *Launcher) getNodes(checkReachability bool) []NodeInfo {
+	nodes := []NodeInfo{}
+	for _, name := range t.ServicesOrder {
+		s := t.Services[name]
+		if s.IsDisabled() {
+			continue
+		}
+		n, ok := s.(p2pNode)
+		if !ok {
+			continue
+		}
+		node := NodeInfo{Service: name, Reachability: ReachabilityUnknown}
+		info, err := n.GetTorInfo()
+		if err != nil {
+			t.Logger.Debugf("Failed to get %s tor info: %s", name, err)
+		} else {
+			node.Tor = info
+		}
+		if t.externalIp != "" {
+			node.ExternalAddress = net.JoinHostPort(t.externalIp, fmt.Sprintf("%d", n.GetP2pPort()))
+		}
+		nodes = append(nodes, node)
+	}
+	if !checkReachability {
+		return nodes
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), reachabilityTimeout+time.Second)
+	defer cancel()
+	checkNodesReachable(ctx, nodes)
+	return nodes
+}
+
+func (t *Launcher) getServices(ctx context.Context) []ServiceInfo {
+	services := []ServiceInfo{}
+	for _, name := range t.ServicesOrder {
+		s := t.Services[name]
+		if s.IsDisabled() {
+			continue
+		}
+		info := ServiceInfo{
+			Name:  name,
+			Mode:  s.GetMode(),
+			Image: s.GetImage(),
+		}
+		if b, ok := s.(interface {
+			GetImageDigest(ctx context.Context) (string, error)
+		}); ok {
+			digest, err := b.GetImageDigest(ctx)
+			if err != nil {
+				t.Logger.Debugf("Failed to get %s image digest: %s", name, err)
+			}
+			info.Digest = digest
+		}
+		rpc, err := s.GetRpcParams()
+		if err != nil {
+			t.Logger.Debugf("Failed to get %s rpc params: %s", name, err)
+		}
+		info.Rpc = sanitizeRpcParams(rpc)
+		services = append(services, info)
+	}
+	return services
+}
+
+// sanitizeRpcParams drops the credentials from the RPC params. config.json
+// keeps them for the proxy, getinfo only shows where the nodes are.
+func sanitizeRpcParams(rpc interface{}) interface{} {
+	switch p := rpc.(type) {
+	case bitcoind.RpcParams:
+		p.Password = ""
+		return p
+	}
+	return rpc
+}
+
+func (t *Launcher) getWalletState(ctx context.Context) string {
+	s, err := t.GetService("opendexd")
+	if err != nil || s.IsDisabled() {
+		return opendexd.WalletUnknown
+	}
+	return s.(*opendexd.Service).GetWalletState(ctx)
+}
+
+func (t *Launcher) GetInfo(ctx context.Context, checkReachability bool) Info {
 	defaultPassword := t.UsingDefaultPassword()
 
 	return Info{
+		Launcher: LauncherInfo{
+			Version: build.Version,
+			Commit:  build.GitCommit,
+		},
+		Network:    string(t.Network),
+		NetworkDir: t.NetworkDir,
+		Services:   t.getServices(ctx),
 		Wallets: WalletsInfo{
+			State:           t.getWalletState(ctx),
 			DefaultPassword: defaultPassword,
 			MnemonicShown:   !defaultPassword,
 		},
@@ -37,11 +179,16 @@ func (t *Launcher) GetInfo() Info {
 			Location:        t.BackupDir,
 			DefaultLocation: t.BackupDir == t.DefaultBackupDir,
 		},
+		Attach:     t.getAttachInfo(),
+		ExternalIp: t.externalIp,
+		Nodes:      t.getNodes(checkReachability),
 	}
 }
 
 func (t *Launcher) _getinfo(c *websocket.Conn, id uint64, args []string) {
-	info, err := json.Marshal(t.GetInfo())
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	info, err := json.Marshal(t.GetInfo(ctx, false))
 	if err != nil {
 		t.respondError(c, id, err)
 	} else {
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Journal is an append-only JSON Lines file recording the events of the launcher
+type Journal struct {
+	File string
+
+	mu sync.Mutex
+}
+
+func NewJournal(file string) *Journal {
+	return &Journal{File: file}
+}
+
+func (t *Journal) Append(e Event) error {
+	j, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, err := os.OpenFile(t.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(j, '\n'))
+	return err
+}
+
+type JournalFilter struct {
+	Service string
+	Type    EventType
+	Since   time.Time
+}
+
+func (t *JournalFilter) Match(e Event) bool {
+	if t.Service != "" && e.Service != t.Service {
+		return false
+	}
+	if t.Type != "" && e.Type != t.Type {
+		return false
+	}
+	if !t.Since.IsZero() && e.Time.Before(t.Since) {
+		return false
+	}
+	return true
+}
+
+// Query returns the recorded events matching filter in chronological order
+func (t *Journal) Query(filter JournalFilter) ([]Event, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, err := os.Open(t.File)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var result []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			// a partially written line should not break the whole journal
+			continue
+		}
+		if filter.Match(e) {
+			result = append(result, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s (line %d): %w", t.File, n, err)
+	}
+	return result, nil
+}
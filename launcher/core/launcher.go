@@ -2,12 +2,12 @@ package core
 
 import (
 	"bufio"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/iancoleman/strcase"
 	"github.com/mitchellh/go-homedir"
 	"github.com/opendexnetwork/opendex-docker/launcher/log"
+	"github.com/opendexnetwork/opendex-docker/launcher/secrets"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/arby"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/bitcoind"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/boltz"
@@ -23,12 +23,13 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Launcher struct {
@@ -51,14 +52,34 @@ type Launcher struct {
 	ConfigFile        string
 
 	PasswordUnsetMarker string
-	ExternalIp          string
+	// ExternalIp is the address advertised to lnd and opendexd peers, "auto"
+	// to discover it
+	ExternalIp string
+	externalIp string
+
+	// MetricsListen is the address of the Prometheus metrics listener of setup
+	// and monitor, empty to disable it
+	MetricsListen string
+	Metrics       *LauncherMetrics
+
+	// Secrets keeps the secrets of services encrypted in the network directory
+	Secrets *secrets.Store
+
+	Alerts    *Alerts
+	alertsErr error
+	Journal   *Journal
+
+	statuses serviceStatuses
+	// backupFailedAt is when the last reported opendexd backup failure happened
+	backupFailedAt time.Time
+
+	notifications sync.WaitGroup
 
 	rootCmd *cobra.Command
-	client  *http.Client
 
 	rootLogger *logrus.Logger
 
-	LogFile *os.File
+	LogFile *log.RotatingFile
 }
 
 func defaultHomeDir() (string, error) {
@@ -214,10 +235,13 @@ func NewLauncher() (*Launcher, error) {
 
 	externalIp := getExternalIp(networkDir)
 
-	config := tls.Config{RootCAs: nil, InsecureSkipVerify: true}
+	arbyInstances, err := loadArbyInstances(filepath.Join(networkDir, "arby.json"))
+	if err != nil {
+		return nil, err
+	}
 
 	logfile := filepath.Join(logsDir, "launcher.log")
-	f, err := os.OpenFile(logfile, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
+	f, err := log.OpenRotatingFile(logfile)
 	if err != nil {
 		return nil, err
 	}
@@ -238,15 +262,18 @@ func NewLauncher() (*Launcher, error) {
 		ConfigFile:          configFile,
 		PasswordUnsetMarker: passwordUnsetMarker,
 		ExternalIp:          externalIp,
-		client: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &config,
-			},
-		},
-		LogFile: f,
+		LogFile:             f,
+		Metrics:             newLauncherMetrics(),
+		Journal:             NewJournal(filepath.Join(networkDir, "journal.jsonl")),
+		Secrets:             secrets.NewStore(filepath.Join(networkDir, "secrets"), filepath.Join(homeDir, "secrets.key")),
 	}
 
-	l.Services, l.ServicesOrder, err = initServices(&l, network)
+	l.Alerts, l.alertsErr = loadAlerts(filepath.Join(networkDir, "alerts.json"), l.Secrets)
+	if l.alertsErr != nil {
+		l.Logger.Errorf("Alerts disabled: %s", l.alertsErr)
+	}
+
+	l.Services, l.ServicesOrder, err = initServices(&l, network, arbyInstances)
 	if err != nil {
 		return nil, err
 	}
@@ -289,6 +316,8 @@ func (t *Launcher) addFlags(serviceName string, configType reflect.Type, default
 			cmd.PersistentFlags().BoolVar(p.(*bool), key, value.(bool), usage)
 		case reflect.Uint16:
 			cmd.PersistentFlags().Uint16Var(p.(*uint16), key, value.(uint16), usage)
+		case reflect.Uint:
+			cmd.PersistentFlags().UintVar(p.(*uint), key, value.(uint), usage)
 		case reflect.Slice:
 			// FIXME differentiate slice item type
 			cmd.PersistentFlags().StringSliceVar(p.(*[]string), key, value.([]string), usage)
@@ -332,7 +361,7 @@ func (t *Launcher) AddServiceFlags(cmd *cobra.Command) error {
 	return nil
 }
 
-func initServices(ctx types.Context, network types.Network) (map[string]types.Service, []string, error) {
+func initServices(ctx types.Context, network types.Network, arbyInstances []string) (map[string]types.Service, []string, error) {
 	var services []types.Service
 	var order []string
 
@@ -487,6 +516,23 @@ func initServices(ctx types.Context, network types.Network) (map[string]types.Se
 		}
 	}
 
+	// more arby instances trading other pairs follow the default one
+	var instances []string
+	for _, instance := range arbyInstances {
+		s, err := arby.New(ctx, fmt.Sprintf("arby-%s", instance))
+		if err != nil {
+			return nil, nil, err
+		}
+		services = append(services, s)
+		instances = append(instances, s.GetName())
+	}
+	for i, name := range order {
+		if name == "arby" {
+			order = append(order[:i+1], append(instances, order[i+1:]...)...)
+			break
+		}
+	}
+
 	result := make(map[string]types.Service)
 	for _, s := range services {
 		result[s.GetName()] = s
@@ -512,6 +558,9 @@ func (t *Launcher) GetService(name string) (types.Service, error) {
 
 // apply configurations into services
 func (t *Launcher) Apply() error {
+	if err := t.resolveExternalIp(); err != nil {
+		return err
+	}
 	for _, name := range t.ServicesOrder {
 		s := t.Services[name]
 		//t.Logger.Debugf("Apply %s", s.GetName())
@@ -527,7 +576,11 @@ func (t *Launcher) GetNetwork() types.Network {
 }
 
 func (t *Launcher) GetExternalIp() string {
-	return ""
+	return t.externalIp
+}
+
+func (t *Launcher) GetSecretStore() types.SecretStore {
+	return t.Secrets
 }
 
 func (t *Launcher) GetNetworkDir() string {
@@ -543,5 +596,6 @@ func (t *Launcher) GetDataDir() string {
 }
 
 func (t *Launcher) Close() {
+	t.flushNotifications(NotifyFlushTimeout)
 	_ = t.LogFile.Close()
 }
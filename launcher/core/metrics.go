@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"github.com/opendexnetwork/opendex-docker/launcher/metrics"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/lnd"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type LauncherMetrics struct {
+	Registry *metrics.Registry
+
+	ServiceUp          *metrics.Metric
+	ServiceReady       *metrics.Metric
+	SyncProgress       *metrics.Metric
+	SyncedHeight       *metrics.Metric
+	TargetHeight       *metrics.Metric
+	WalletLocked       *metrics.Metric
+	SetupPhaseDuration *metrics.Metric
+	ContainerRestarts  *metrics.Metric
+	Rescues            *metrics.Metric
+	ContainerCpu       *metrics.Metric
+	ContainerMemory    *metrics.Metric
+	ContainerMemLimit  *metrics.Metric
+}
+
+func newLauncherMetrics() *LauncherMetrics {
+	r := metrics.NewRegistry()
+	return &LauncherMetrics{
+		Registry:           r,
+		ServiceUp:          r.NewGauge("opendex_service_up", "Whether the service container is running", "service"),
+		ServiceReady:       r.NewGauge("opendex_service_ready", "Whether the service status is Ready", "service"),
+		SyncProgress:       r.NewGauge("opendex_service_sync_progress", "Chain syncing progress of the service (0-1)", "service"),
+		SyncedHeight:       r.NewGauge("opendex_service_synced_height", "Block height the service has synced to", "service"),
+		TargetHeight:       r.NewGauge("opendex_service_target_height", "Block height the service is syncing to", "service"),
+		WalletLocked:       r.NewGauge("opendex_wallet_locked", "Whether the wallet of the service is locked", "service"),
+		SetupPhaseDuration: r.NewGauge("opendex_setup_phase_duration_seconds", "Time spent in each phase of the last setup", "phase"),
+		ContainerRestarts:  r.NewGauge("opendex_container_restarts", "Number of times Docker restarted the service container", "service"),
+		Rescues:            r.NewCounter("opendex_service_rescues_total", "Number of rescues of stuck services by outcome", "service", "outcome"),
+		ContainerCpu:       r.NewGauge("opendex_container_cpu_usage_ratio", "CPU usage of the service container (1 = one core)", "service"),
+		ContainerMemory:    r.NewGauge("opendex_container_memory_usage_bytes", "Memory usage of the service container", "service"),
+		ContainerMemLimit:  r.NewGauge("opendex_container_memory_limit_bytes", "Memory limit of the service container", "service"),
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// observeStatus updates status derived metrics of service name
+func (t *LauncherMetrics) observeStatus(name string, status string) {
+	// services only report their own status when their containers are running
+	up := status == "Container running" || (status != "" && !strings.HasPrefix(status, "Container "))
+	t.ServiceUp.Set(boolToFloat(up), name)
+	t.ServiceReady.Set(boolToFloat(isReady(status)), name)
+	t.WalletLocked.Set(boolToFloat(strings.HasPrefix(status, "Wallet locked")), name)
+
+	if isReady(status) {
+		t.SyncProgress.Set(1, name)
+	} else if synced, total, ok := lnd.ParseSyncingText(status); ok {
+		if total > 0 {
+			t.SyncProgress.Set(float64(synced)/float64(total), name)
+		}
+		t.SyncedHeight.Set(float64(synced), name)
+		t.TargetHeight.Set(float64(total), name)
+	}
+}
+
+func (t *LauncherMetrics) observePhase(phase string, start time.Time) {
+	t.SetupPhaseDuration.Set(time.Since(start).Seconds(), phase)
+}
+
+// collectContainerMetrics reads restart count and resource usage of the
+// service containers from Docker
+func (t *Launcher) collectContainerMetrics(ctx context.Context, name string) {
+	s, err := t.GetService(name)
+	if err != nil {
+		return
+	}
+	b, ok := s.(interface {
+		GetRestartCount(ctx context.Context) (int, error)
+		GetStats(ctx context.Context) (*base.Stats, error)
+	})
+	if !ok {
+		return
+	}
+	if n, err := b.GetRestartCount(ctx); err == nil {
+		t.Metrics.ContainerRestarts.Set(float64(n), name)
+	}
+	if !s.IsRunning() {
+		return
+	}
+	stats, err := b.GetStats(ctx)
+	if err != nil {
+		t.Logger.Debugf("Failed to get %s stats: %s", name, err)
+		return
+	}
+	t.Metrics.ContainerCpu.Set(stats.CpuUsage, name)
+	t.Metrics.ContainerMemory.Set(float64(stats.MemoryUsage), name)
+	t.Metrics.ContainerMemLimit.Set(float64(stats.MemoryLimit), name)
+}
+
+func (t *Launcher) serveMetrics(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", t.Metrics.Registry.Handler())
+
+	server := &http.Server{
+		Addr:    t.MetricsListen,
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	t.Logger.Debugf("Serving metrics on http://%s/metrics", t.MetricsListen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/opendexd"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// a service is considered stuck when its status doesn't change for this long
+	ServiceStuckDuration = ServiceStuckThreshold * StatusQueryInterval
+)
+
+type serviceStatus struct {
+	status       string
+	since        time.Time
+	stuckAlerted bool
+}
+
+type serviceStatuses struct {
+	mu       sync.Mutex
+	statuses map[string]*serviceStatus
+}
+
+// isReady tells whether status is "Ready", optionally followed by details,
+// e.g. "Ready (2 channels, 0.5000 ETH collateral)"
+func isReady(status string) bool {
+	return status == "Ready" || strings.HasPrefix(status, "Ready (")
+}
+
+// update records the latest status of service name and returns the previous
+// one. stuck is true only the first time the status is found unchanged for
+// ServiceStuckDuration.
+func (t *serviceStatuses) update(name string, status string) (prev string, changed bool, stuck bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.statuses == nil {
+		t.statuses = make(map[string]*serviceStatus)
+	}
+	s, ok := t.statuses[name]
+	if !ok || s.status != status {
+		if ok {
+			prev = s.status
+		}
+		t.statuses[name] = &serviceStatus{status: status, since: time.Now()}
+		return prev, true, false
+	}
+	if !isReady(status) && !s.stuckAlerted && time.Since(s.since) >= ServiceStuckDuration {
+		s.stuckAlerted = true
+		return status, false, true
+	}
+	return status, false, false
+}
+
+// setStatus is called whenever the launcher learns the latest status of a
+// service, either while bringing it up or while monitoring it
+func (t *Launcher) setStatus(name string, status string) {
+	t.Metrics.observeStatus(name, status)
+	prev, changed, stuck := t.statuses.update(name, status)
+	if changed {
+		t.emit(Event{
+			Type:    EventStatusChanged,
+			Service: name,
+			From:    prev,
+			To:      status,
+			Message: fmt.Sprintf("%s: %q -> %q", name, prev, status),
+		})
+		if status == "Waiting for channels" {
+			t.emit(Event{
+				Type:    EventNoChannels,
+				Service: name,
+				Message: fmt.Sprintf("%s has no active channels", name),
+			})
+		}
+	}
+	if stuck {
+		t.emit(Event{
+			Type:    EventServiceStuck,
+			Service: name,
+			To:      status,
+			Message: fmt.Sprintf("%s has been stuck at %q for %s", name, status, ServiceStuckDuration),
+		})
+	}
+}
+
+func (t *Launcher) checkServices(ctx context.Context) {
+	for _, name := range t.ServicesOrder {
+		s := t.Services[name]
+		if s.IsDisabled() {
+			continue
+		}
+		status, err := s.GetStatus(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.Logger.Debugf("[monitor] Failed to get %s status: %s", name, err)
+			status = "Container missing"
+		}
+		t.setStatus(name, status)
+		t.collectContainerMetrics(ctx, name)
+	}
+	t.checkBackup()
+}
+
+// checkBackup reports the failure of the opendexd backup process once
+func (t *Launcher) checkBackup() {
+	s, ok := t.runningService("opendexd")
+	if !ok {
+		return
+	}
+	failure, at, err := s.(*opendexd.Service).GetBackupFailure()
+	if err != nil {
+		t.Logger.Debugf("[monitor] Failed to check opendexd backup: %s", err)
+		return
+	}
+	if failure == "" || !at.After(t.backupFailedAt) {
+		return
+	}
+	t.backupFailedAt = at
+	t.emit(Event{
+		Type:    EventBackupFailed,
+		Service: "opendexd",
+		To:      t.BackupDir,
+		Outcome: OutcomeFailed,
+		Message: fmt.Sprintf("Backup to %s stopped: %s", t.BackupDir, failure),
+	})
+}
+
+func (t *Launcher) monitor(ctx context.Context) {
+	for {
+		t.checkServices(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(StatusQueryInterval):
+		}
+	}
+}
+
+// Monitor keeps polling the status of all enabled services until ctx is
+// cancelled and serves the collected metrics if MetricsListen is set
+func (t *Launcher) Monitor(ctx context.Context) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.Chdir(t.NetworkDir); err != nil {
+		return fmt.Errorf("change directory: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	if t.MetricsListen != "" {
+		go func() {
+			errCh <- t.serveMetrics(ctx)
+		}()
+	}
+
+	go t.monitor(ctx)
+
+	select {
+	case <-ctx.Done():
+		return errInterrupted
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("serve metrics: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/utils"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, e Event) error
+}
+
+type NotifierConfig struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// webhook
+	Url     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// smtp
+	Host     string   `json:"host,omitempty"`
+	Port     uint16   `json:"port,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+
+	// command
+	Command []string `json:"command,omitempty"`
+}
+
+func newNotifier(c NotifierConfig) (Notifier, error) {
+	switch c.Type {
+	case "webhook":
+		if c.Url == "" {
+			return nil, errors.New("url is required")
+		}
+		return &WebhookNotifier{Url: c.Url, Headers: c.Headers}, nil
+	case "smtp":
+		if c.Host == "" || c.From == "" || len(c.To) == 0 {
+			return nil, errors.New("host, from and to are required")
+		}
+		port := c.Port
+		if port == 0 {
+			port = 587
+		}
+		return &SmtpNotifier{
+			Addr:     fmt.Sprintf("%s:%d", c.Host, port),
+			Host:     c.Host,
+			Username: c.Username,
+			Password: c.Password,
+			From:     c.From,
+			To:       c.To,
+		}, nil
+	case "command":
+		if len(c.Command) == 0 {
+			return nil, errors.New("command is required")
+		}
+		return &CommandNotifier{Command: c.Command}, nil
+	case "desktop":
+		return &DesktopNotifier{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier type: %s", c.Type)
+	}
+}
+
+// WebhookNotifier posts the event as JSON to Url
+type WebhookNotifier struct {
+	Url     string
+	Headers map[string]string
+}
+
+func (t *WebhookNotifier) Notify(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", t.Url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("[http %d] %s", resp.StatusCode, t.Url)
+	}
+	return nil
+}
+
+// SmtpNotifier sends the event by email
+type SmtpNotifier struct {
+	Addr     string
+	Host     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (t *SmtpNotifier) Notify(ctx context.Context, e Event) error {
+	var auth smtp.Auth
+	if t.Username != "" {
+		auth = smtp.PlainAuth("", t.Username, t.Password, t.Host)
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("From: %s\r\n", t.From))
+	b.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(t.To, ", ")))
+	b.WriteString(fmt.Sprintf("Subject: [opendex-docker] %s\r\n", e.Title()))
+	b.WriteString("\r\n")
+	b.WriteString(e.String())
+	b.WriteString("\r\n")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return t.send(ctx, auth, []byte(b.String()))
+}
+
+// send is smtp.SendMail with the connection bound to ctx
+func (t *SmtpNotifier) send(ctx context.Context, auth smtp.Auth, msg []byte) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", t.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	// unblock the exchange when ctx is canceled before the deadline
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+
+	c, err := smtp.NewClient(conn, t.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: t.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(t.From); err != nil {
+		return err
+	}
+	for _, to := range t.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// CommandNotifier runs a local command with the event passed in environment
+// variables and as JSON on stdin
+type CommandNotifier struct {
+	Command []string
+}
+
+func (t *CommandNotifier) Notify(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	cmd := exec.Command(t.Command[0], t.Command[1:]...)
+	cmd.Env = append(os.Environ(),
+		"OPENDEX_EVENT_TYPE="+string(e.Type),
+		"OPENDEX_EVENT_SERVICE="+e.Service,
+		"OPENDEX_EVENT_FROM="+e.From,
+		"OPENDEX_EVENT_TO="+e.To,
+		"OPENDEX_EVENT_MESSAGE="+e.Message,
+	)
+	cmd.Stdin = bytes.NewReader(body)
+	return utils.Run(ctx, cmd)
+}
+
+// DesktopNotifier shows the event as a desktop notification
+type DesktopNotifier struct {
+}
+
+func (t *DesktopNotifier) Notify(ctx context.Context, e Event) error {
+	title := "opendex-docker: " + e.Title()
+	msg := e.String()
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.Command("notify-send", title, msg)
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", msg, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, '%s', '%s', 'Info')
+Start-Sleep -Seconds 10
+$n.Dispose()`, strings.ReplaceAll(title, "'", "''"), strings.ReplaceAll(msg, "'", "''"))
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	return utils.Run(ctx, cmd)
+}
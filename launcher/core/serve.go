@@ -4,6 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/gorilla/websocket"
+	"github.com/opendexnetwork/opendex-docker/launcher/log"
+)
+
+var (
+	// the params of these methods carry wallet passwords or seeds
+	sensitiveMethods = map[string]bool{
+		"create":  true,
+		"unlock":  true,
+		"restore": true,
+	}
 )
 
 type Request struct {
@@ -19,10 +29,8 @@ func (t *Launcher) serve(ctx context.Context, c *websocket.Conn) {
 			t.Logger.Errorf("read: %s", err)
 			return
 		}
-		t.Logger.Debugf("[attach] recv: %s", message)
-
 		if err := t.handleMessage(c, message); err != nil {
-			t.Logger.Errorf("handle %s: %s", message, err)
+			t.Logger.Errorf("handle %s: %s", log.Redact(string(message)), err)
 		}
 	}
 }
@@ -30,8 +38,10 @@ func (t *Launcher) serve(ctx context.Context, c *websocket.Conn) {
 func (t *Launcher) handleMessage(c *websocket.Conn, msg []byte) error {
 	var req Request
 	if err := json.Unmarshal(msg, &req); err != nil {
+		t.Logger.Debugf("[attach] recv: %s", log.Redact(string(msg)))
 		return err
 	}
+	t.Logger.WithField("request_id", req.Id).Debugf("[attach] recv: %s", redactRequest(req))
 
 	switch req.Method {
 	case "getinfo":
@@ -42,6 +52,21 @@ func (t *Launcher) handleMessage(c *websocket.Conn, msg []byte) error {
 	return nil
 }
 
+func redactRequest(req Request) string {
+	if sensitiveMethods[req.Method] {
+		params := make([]string, len(req.Params))
+		for i := range params {
+			params[i] = log.Redacted
+		}
+		req.Params = params
+	}
+	j, err := json.Marshal(req)
+	if err != nil {
+		return err.Error()
+	}
+	return string(j)
+}
+
 func (t *Launcher) respondError(c *websocket.Conn, reqId uint64, err error) {
 	var resp = make(map[string]interface{})
 	resp["result"] = nil
@@ -51,7 +76,7 @@ func (t *Launcher) respondError(c *websocket.Conn, reqId uint64, err error) {
 	if err != nil {
 		panic(err)
 	}
-	t.Logger.Debugf("[attach] send: %s", j)
+	t.Logger.WithField("request_id", reqId).Debugf("[attach] send: %s", j)
 	err = c.WriteMessage(websocket.TextMessage, j)
 	if err != nil {
 		panic(err)
@@ -67,12 +92,9 @@ func (t *Launcher) respondResult(c *websocket.Conn, reqId uint64, result string)
 	if err != nil {
 		panic(err)
 	}
-	t.Logger.Debugf("[attach] send: %s", j)
+	t.Logger.WithField("request_id", reqId).Debugf("[attach] send: %s", j)
 	err = c.WriteMessage(websocket.TextMessage, j)
 	if err != nil {
 		panic(err)
 	}
 }
-
-
-
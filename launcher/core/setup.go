@@ -1,17 +1,15 @@
 package core
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/gorilla/websocket"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/proxy"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/proxy/client"
 	"github.com/opendexnetwork/opendex-docker/launcher/utils"
 	"golang.org/x/sync/errgroup"
-	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
@@ -42,7 +40,9 @@ func (t *Launcher) Setup(ctx context.Context, pull bool) error {
 	t.Logger.Debugf("Setup %s (%s)", t.Network, t.NetworkDir)
 
 	// Checking Docker
-	err := utils.Run(ctx, exec.Command("docker", "info"))
+	err := t.runPhase("docker", func() error {
+		return utils.Run(ctx, exec.Command("docker", "info"))
+	})
 	if err != nil {
 		return fmt.Errorf("docker is not ready: %w", err)
 	}
@@ -69,18 +69,33 @@ func (t *Launcher) Setup(ctx context.Context, pull bool) error {
 		return fmt.Errorf("write log: %w", err)
 	}
 
-	if err := t.Gen(ctx); err != nil {
+	if t.MetricsListen != "" {
+		go func() {
+			if err := t.serveMetrics(ctx); err != nil {
+				t.Logger.Errorf("Serve metrics: %s", err)
+			}
+		}()
+	}
+
+	// the probe goes first so that connext is generated with the healthy
+	// Ethereum providers first
+	t.Logger.Debugf("Probe external nodes")
+	if err := t.runPhase("probe", func() error { return t.probeExternalNodes(ctx) }); err != nil {
+		return fmt.Errorf("probe external nodes: %w", err)
+	}
+
+	if err := t.runPhase("gen", func() error { return t.Gen(ctx) }); err != nil {
 		return fmt.Errorf("generate files: %w", err)
 	}
 
 	if pull {
-		if err := t.Pull(ctx); err != nil {
+		if err := t.runPhase("pull", func() error { return t.Pull(ctx) }); err != nil {
 			return fmt.Errorf("pull: %w", err)
 		}
 	}
 
 	t.Logger.Debugf("Bring up proxy")
-	if err := t.upProxy(ctx); err != nil {
+	if err := t.runPhase("proxy", func() error { return t.upProxy(ctx) }); err != nil {
 		return fmt.Errorf("up proxy: %w", err)
 	}
 
@@ -95,23 +110,36 @@ func (t *Launcher) Setup(ctx context.Context, pull bool) error {
 		}
 	}()
 
+	t.Logger.Debugf("Bring up layer 1 services")
+	if err := t.runPhase("layer1", func() error { return t.upLayer1(ctx) }); err != nil {
+		return fmt.Errorf("up layer1: %w", err)
+	}
+
 	t.Logger.Debugf("Bring up layer 2 services")
-	if err := t.upLayer2(ctx); err != nil {
+	if err := t.runPhase("layer2", func() error { return t.upLayer2(ctx) }); err != nil {
 		return fmt.Errorf("up layer2: %w", err)
 	}
 
 	t.Logger.Debugf("Bring up opendexd")
-	if err := t.upOpendexd(ctx); err != nil {
+	if err := t.runPhase("opendexd", func() error { return t.upOpendexd(ctx) }); err != nil {
 		return fmt.Errorf("up opendexd: %w", err)
 	}
 
 	t.Logger.Debugf("Bring up additional services")
-	if err := t.upArby(ctx); err != nil {
+	if err := t.runPhase("arby", func() error { return t.upArby(ctx) }); err != nil {
 		return fmt.Errorf("up arby: %w", err)
 	}
-	if err := t.upBoltz(ctx); err != nil {
+	if err := t.runPhase("boltz", func() error { return t.upBoltz(ctx) }); err != nil {
 		return fmt.Errorf("up boltz: %w", err)
 	}
+	if err := t.runPhase("webui", func() error { return t.upWebui(ctx) }); err != nil {
+		return fmt.Errorf("up webui: %w", err)
+	}
+
+	// the monitor feeds both the metrics and the alerts
+	if t.MetricsListen != "" || t.Alerts != nil {
+		go t.monitor(ctx)
+	}
 
 	_, err = f.WriteString("Start shell\n")
 	if err != nil {
@@ -125,7 +153,42 @@ func (t *Launcher) Setup(ctx context.Context, pull bool) error {
 	return nil
 }
 
+// runPhase runs one phase of Setup and records how long it took and whether it succeeded
+func (t *Launcher) runPhase(phase string, f func() error) error {
+	start := time.Now()
+	err := f()
+	t.Metrics.observePhase(phase, start)
+	e := Event{
+		Type:    EventSetupPhase,
+		Phase:   phase,
+		Outcome: outcome(err),
+		Message: fmt.Sprintf("Setup phase %s finished in %s", phase, time.Since(start).Round(time.Millisecond)),
+	}
+	if err != nil {
+		e.Message = fmt.Sprintf("Setup phase %s failed after %s: %s", phase, time.Since(start).Round(time.Millisecond), err)
+	}
+	t.emit(e)
+	return err
+}
+
 func (t *Launcher) upProxy(ctx context.Context) error {
+	p, err := t.getProxy()
+	if err != nil {
+		return err
+	}
+	if p.IsRunning() {
+		// the certificate may have been rotated by any command since the
+		// proxy started
+		current, err := p.ServesCurrentCert(ctx)
+		if err != nil {
+			t.Logger.Debugf("Failed to check the proxy TLS certificate: %s", err)
+		} else if !current {
+			t.Logger.Infof("Restarting proxy with the new TLS certificate")
+			if err := p.Restart(ctx); err != nil {
+				return fmt.Errorf("restart: %w", err)
+			}
+		}
+	}
 	return t.upService(ctx, "proxy", func(status string) bool {
 		if status == "Ready" {
 			return true
@@ -134,7 +197,28 @@ func (t *Launcher) upProxy(ctx context.Context) error {
 	})
 }
 
+// lndBackends maps lnd services to their full node backends
+var lndBackends = map[string]string{
+	"lndbtc": "bitcoind",
+	"lndltc": "litecoind",
+}
+
+// waitLndBackend blocks until the native full node backend of lnd is synced
+func (t *Launcher) waitLndBackend(ctx context.Context, name string) error {
+	backend, ok := t.Services[lndBackends[name]]
+	if !ok || backend.GetMode() != "native" {
+		return nil
+	}
+	t.Logger.Debugf("Waiting for %s to be ready before starting %s", backend.GetName(), name)
+	return t.upService(ctx, backend.GetName(), func(status string) bool {
+		return status == "Ready"
+	})
+}
+
 func (t *Launcher) upLnd(ctx context.Context, name string) error {
+	if err := t.waitLndBackend(ctx, name); err != nil {
+		return fmt.Errorf("wait for backend: %w", err)
+	}
 	return t.upService(ctx, name, func(status string) bool {
 		if status == "Ready" {
 			return true
@@ -148,105 +232,55 @@ func (t *Launcher) upLnd(ctx context.Context, name string) error {
 		if strings.HasPrefix(status, "Wallet locked") {
 			return true
 		}
+		if strings.HasPrefix(status, "Waiting for wallet") {
+			// opendexd will create the wallet
+			return true
+		}
 		return false
 	})
 }
 
 func (t *Launcher) upConnext(ctx context.Context) error {
 	return t.upService(ctx, "connext", func(status string) bool {
-		if status == "Ready" {
+		if isReady(status) {
 			return true
 		}
 		return false
 	})
 }
 
-func (t *Launcher) getProxyApiUrl() (string, error) {
+func (t *Launcher) getProxy() (*proxy.Service, error) {
 	s, err := t.GetService("proxy")
 	if err != nil {
-		return "", fmt.Errorf("get service: %w", err)
-	}
-	rpc, err := s.GetRpcParams()
-	if err != nil {
-		return "", fmt.Errorf("get rpc params: %w", err)
+		return nil, fmt.Errorf("get service: %w", err)
 	}
-	return rpc.(proxy.RpcParams).ToUri(), nil
+	return s.(*proxy.Service), nil
 }
 
-type ApiError struct {
-	Message string `json:"message"`
-}
-
-func (t *Launcher) createWallets(ctx context.Context, password string) error {
-	apiUrl, err := t.getProxyApiUrl()
-	if err != nil {
-		return fmt.Errorf("get proxy api url: %w", err)
-	}
-	createUrl := fmt.Sprintf("%s/api/v1/opendexd/create", apiUrl)
-	payload := map[string]interface{}{
-		"password": password,
-	}
-	body, err := json.Marshal(payload)
+// getProxyTlsConfig returns the TLS configuration pinning the proxy
+// certificate, or nil when the proxy API is served without TLS
+func (t *Launcher) getProxyTlsConfig() (*tls.Config, error) {
+	p, err := t.getProxy()
 	if err != nil {
-		return fmt.Errorf("encode payload: %w", err)
+		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, "POST", createUrl, bytes.NewBuffer(body))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := t.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("do reqeust: %w", err)
+	if p.RpcParams.Scheme != "https" {
+		return nil, nil
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		var body ApiError
-		err := json.NewDecoder(resp.Body).Decode(&body)
-		if err != nil {
-			return fmt.Errorf("[http %d] decode error: %w", resp.StatusCode, err)
-		}
-		return fmt.Errorf("[http %d] %s", resp.StatusCode, body.Message)
-	}
-
-	return nil
+	return p.GetTlsConfig()
 }
 
-func (t *Launcher) unlockWallets(ctx context.Context, password string) error {
-	apiUrl, err := t.getProxyApiUrl()
-	if err != nil {
-		return fmt.Errorf("get proxy api url: %w", err)
-	}
-	createUrl := fmt.Sprintf("%s/api/v1/opendexd/unlock", apiUrl)
-	payload := map[string]interface{}{
-		"password": password,
-	}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("encode payload: %w", err)
-	}
-	req, err := http.NewRequestWithContext(ctx, "POST", createUrl, bytes.NewBuffer(body))
+// getProxyApi returns a client of the proxy API with the pinned certificate
+func (t *Launcher) getProxyApi() (*client.Client, error) {
+	p, err := t.getProxy()
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := t.client.Do(req)
+	config, err := t.getProxyTlsConfig()
 	if err != nil {
-		return fmt.Errorf("do reqeust: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		var body ApiError
-		err := json.NewDecoder(resp.Body).Decode(&body)
-		if err != nil {
-			return fmt.Errorf("[http %d] decode error: %w", resp.StatusCode, err)
-		}
-		return fmt.Errorf("[http %d] %s", resp.StatusCode, body.Message)
-	}
-
-	return nil
+	return client.New(p.RpcParams.ToUri(), config), nil
 }
 
 func (t *Launcher) upService(ctx context.Context, name string, checkFunc func(string) bool) error {
@@ -274,7 +308,21 @@ func (t *Launcher) upService(ctx context.Context, name string, checkFunc func(st
 	for {
 		if count >= ServiceStuckThreshold {
 			if ctx.Value("rescue").(bool) {
-				if s.Rescue(ctx) {
+				ok := s.Rescue(ctx)
+				e := Event{
+					Type:    EventRescue,
+					Service: name,
+					From:    prevStatus,
+					Outcome: OutcomeOk,
+					Message: fmt.Sprintf("Rescued %s stuck at %q", name, prevStatus),
+				}
+				if !ok {
+					e.Outcome = OutcomeFailed
+					e.Message = fmt.Sprintf("Failed to rescue %s stuck at %q", name, prevStatus)
+				}
+				t.Metrics.Rescues.Inc(name, e.Outcome)
+				t.emit(e)
+				if ok {
 					count = 0
 				} else {
 					break
@@ -295,6 +343,7 @@ func (t *Launcher) upService(ctx context.Context, name string, checkFunc func(st
 			prevStatus = ""
 		} else {
 			t.Logger.Debugf("[status] %s: %s", name, status)
+			t.setStatus(name, status)
 			if prevStatus == status {
 				count++
 			} else {
@@ -318,6 +367,12 @@ func (t *Launcher) upService(ctx context.Context, name string, checkFunc func(st
 	}
 
 	if count >= ServiceStuckThreshold {
+		t.emit(Event{
+			Type:    EventServiceStuck,
+			Service: name,
+			To:      prevStatus,
+			Message: fmt.Sprintf("%s is stuck at %q", name, prevStatus),
+		})
 		return fmt.Errorf("%s stuck", name)
 	}
 
@@ -333,11 +388,13 @@ func (t *Launcher) upOpendexd(ctx context.Context) error {
 			return true
 		}
 		if strings.HasPrefix(status, "Wallet missing") {
-			if err := t.createWallets(ctx, DefaultWalletPassword); err != nil {
+			err := t.createWallets(ctx)
+			t.emitWalletEvent("create", err)
+			if err != nil {
 				t.Logger.Errorf("Failed to create: %s", err)
 				return false
 			}
-			_, err := os.Create(t.PasswordUnsetMarker)
+			_, err = os.Create(t.PasswordUnsetMarker)
 			if err != nil {
 				t.Logger.Errorf("Failed to create .default-password: %s", err)
 				return false
@@ -346,7 +403,9 @@ func (t *Launcher) upOpendexd(ctx context.Context) error {
 		}
 		if strings.HasPrefix(status, "Wallet locked") {
 			if t.UsingDefaultPassword() {
-				if err := t.unlockWallets(ctx, DefaultWalletPassword); err != nil {
+				err := t.unlockWallets(ctx)
+				t.emitWalletEvent("unlock", err)
+				if err != nil {
 					t.Logger.Errorf("Failed to unlock: %s", err)
 					if strings.Contains(err.Error(), "password is incorrect") {
 						_ = os.Remove(t.PasswordUnsetMarker)
@@ -362,10 +421,56 @@ func (t *Launcher) upOpendexd(ctx context.Context) error {
 	})
 }
 
+func (t *Launcher) createWallets(ctx context.Context) error {
+	api, err := t.getProxyApi()
+	if err != nil {
+		return err
+	}
+	_, err = api.Create(ctx, DefaultWalletPassword)
+	return err
+}
+
+func (t *Launcher) unlockWallets(ctx context.Context) error {
+	api, err := t.getProxyApi()
+	if err != nil {
+		return err
+	}
+	_, err = api.Unlock(ctx, DefaultWalletPassword)
+	return err
+}
+
+func (t *Launcher) emitWalletEvent(action string, err error) {
+	e := Event{
+		Type:    EventWallet,
+		Service: "opendexd",
+		Phase:   action,
+		Outcome: outcome(err),
+		Message: fmt.Sprintf("Wallet %s with the default password succeeded", action),
+	}
+	if err != nil {
+		e.Message = fmt.Sprintf("Wallet %s with the default password failed: %s", action, err)
+	}
+	t.emit(e)
+}
+
 func (t *Launcher) upArby(ctx context.Context) error {
-	return t.upService(ctx, "arby", func(status string) bool {
-		return true
-	})
+	if err := t.validateArby(); err != nil {
+		return err
+	}
+	for _, name := range t.getArbyServices() {
+		err := t.upService(ctx, name, func(status string) bool {
+			if strings.HasPrefix(status, "Disconnected from CEX") {
+				// the CEX credentials or connection can be fixed without restarting the setup
+				t.Logger.Warnf("%s: %s", name, status)
+				return true
+			}
+			return isReady(status)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
 }
 
 func (t *Launcher) upBoltz(ctx context.Context) error {
@@ -374,6 +479,12 @@ func (t *Launcher) upBoltz(ctx context.Context) error {
 	})
 }
 
+func (t *Launcher) upWebui(ctx context.Context) error {
+	return t.upService(ctx, "webui", func(status string) bool {
+		return status == "Ready"
+	})
+}
+
 func (t *Launcher) attachToProxy(ctx context.Context) error {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt)
@@ -390,13 +501,20 @@ func (t *Launcher) attachToProxy(ctx context.Context) error {
 
 	port := params.(proxy.RpcParams).Port
 
-	u := url.URL{Scheme: "wss", Host: fmt.Sprintf("127.0.0.1:%d", port), Path: "/launcher"}
-	t.Logger.Debugf("Connecting to %s", u.String())
+	config, err := t.getProxyTlsConfig()
+	if err != nil {
+		return err
+	}
 
-	config := tls.Config{RootCAs: nil, InsecureSkipVerify: true}
+	scheme := "ws"
+	if config != nil {
+		scheme = "wss"
+	}
+	u := url.URL{Scheme: scheme, Host: fmt.Sprintf("127.0.0.1:%d", port), Path: "/launcher"}
+	t.Logger.Debugf("Connecting to %s", u.String())
 
-	dialer := websocket.DefaultDialer
-	dialer.TLSClientConfig = &config
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = config
 	c, _, err := dialer.Dial(u.String(), nil)
 	if err != nil {
 		return err
@@ -404,6 +522,8 @@ func (t *Launcher) attachToProxy(ctx context.Context) error {
 	defer c.Close()
 
 	t.Logger.Debugf("Attached to proxy")
+	t.markAttached(u.String())
+	defer t.markDetached()
 
 	done := make(chan struct{})
 
@@ -432,6 +552,56 @@ func (t *Launcher) attachToProxy(ctx context.Context) error {
 	}
 }
 
+type prober interface {
+	Probe(ctx context.Context) error
+}
+
+// probeExternalNodes verifies the external blockchain nodes so that a
+// misconfigured node stops the setup instead of the layer 2 services waiting
+// on it forever
+func (t *Launcher) probeExternalNodes(ctx context.Context) error {
+	for _, name := range []string{"bitcoind", "litecoind", "geth"} {
+		s, ok := t.Services[name]
+		if !ok {
+			continue
+		}
+		p, ok := s.(prober)
+		if !ok {
+			continue
+		}
+		if err := p.Probe(ctx); err != nil {
+			return err
+		}
+	}
+	// the order of the Ethereum providers of connext depends on their health
+	if s, ok := t.Services["connext"]; ok && !s.IsDisabled() {
+		if err := s.Apply(t.ServicesConfig["connext"]); err != nil {
+			return fmt.Errorf("apply connext: %w", err)
+		}
+	}
+	return nil
+}
+
+// upLayer1 brings up the native blockchain nodes. They are disabled in
+// other modes.
+func (t *Launcher) upLayer1(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+
+	for _, name := range []string{"bitcoind", "litecoind", "geth"} {
+		if _, ok := t.Services[name]; !ok {
+			continue
+		}
+		name := name
+		g.Go(func() error {
+			return t.upService(ctx, name, func(status string) bool {
+				return true
+			})
+		})
+	}
+
+	return g.Wait()
+}
+
 func (t *Launcher) upLayer2(ctx context.Context) error {
 	g, ctx := errgroup.WithContext(ctx)
 
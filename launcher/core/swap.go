@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/boltz"
+	"strings"
+)
+
+// SwapEntry is a swap of any kind listed by ListSwaps. Amounts are in
+// satoshis.
+type SwapEntry struct {
+	Chain         string `json:"chain"`
+	Type          string `json:"type"`
+	Id            string `json:"id"`
+	State         string `json:"state"`
+	Status        string `json:"status"`
+	Amount        int64  `json:"amount"`
+	Transaction   string `json:"transaction"`
+	TimeoutHeight uint32 `json:"timeoutHeight"`
+	Error         string `json:"error,omitempty"`
+}
+
+func (t *Launcher) getBoltz() (*boltz.Service, error) {
+	s, ok := t.runningService("boltz")
+	if !ok {
+		return nil, errors.New("boltz is not running")
+	}
+	return s.(*boltz.Service), nil
+}
+
+func swapEntry(chain string, typ string, s boltz.Swap) SwapEntry {
+	tx := s.LockupTransactionId
+	if s.RefundTransactionId != "" {
+		tx = s.RefundTransactionId
+	}
+	return SwapEntry{
+		Chain:         chain,
+		Type:          typ,
+		Id:            s.Id,
+		State:         s.State,
+		Status:        s.Status,
+		Amount:        s.ExpectedAmount,
+		Transaction:   tx,
+		TimeoutHeight: s.TimeoutBlockHeight,
+		Error:         s.Error,
+	}
+}
+
+// ListSwaps lists the swaps of all chains
+func (t *Launcher) ListSwaps(ctx context.Context) ([]SwapEntry, error) {
+	result := []SwapEntry{}
+	err := t.inNetworkDir(func() error {
+		b, err := t.getBoltz()
+		if err != nil {
+			return err
+		}
+		for _, chain := range boltz.Chains {
+			swaps, err := b.ListSwaps(ctx, chain)
+			if err != nil {
+				return fmt.Errorf("list %s swaps: %w", chain, err)
+			}
+			for _, s := range swaps.Swaps {
+				result = append(result, swapEntry(chain, "in", s))
+			}
+			for _, c := range swaps.ChannelCreations {
+				result = append(result, swapEntry(chain, "channel", c.Swap))
+			}
+			for _, s := range swaps.ReverseSwaps {
+				tx := s.LockupTransactionId
+				if s.ClaimTransactionId != "" {
+					tx = s.ClaimTransactionId
+				}
+				result = append(result, SwapEntry{
+					Chain:         chain,
+					Type:          "out",
+					Id:            s.Id,
+					State:         s.State,
+					Status:        s.Status,
+					Amount:        s.OnchainAmount,
+					Transaction:   tx,
+					TimeoutHeight: s.TimeoutBlockHeight,
+					Error:         s.Error,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SwapIn swaps amount satoshis from the onchain wallet into the channels of
+// chain, SwapOut the other way around
+// GetSwapFee quotes the fee in satoshis of a swap in (or out when reverse) of
+// amount satoshis
+func (t *Launcher) GetSwapFee(ctx context.Context, chain string, amount uint64, reverse bool) (uint64, error) {
+	var fee uint64
+	err := t.inNetworkDir(func() error {
+		b, err := t.getBoltz()
+		if err != nil {
+			return err
+		}
+		fee, err = b.GetFee(ctx, chain, amount, reverse)
+		return err
+	})
+	return fee, err
+}
+
+// SwapIn creates a swap unless its fee exceeds maxFee satoshis
+func (t *Launcher) SwapIn(ctx context.Context, chain string, amount uint64, maxFee uint64) (string, error) {
+	return t.swap(ctx, "swap", chain, amount, func(b *boltz.Service) (string, string, error) {
+		return b.CreateSwap(ctx, chain, amount, maxFee)
+	})
+}
+
+// SwapOut creates a reverse swap unless its fee exceeds maxFee satoshis
+func (t *Launcher) SwapOut(ctx context.Context, chain string, amount uint64, address string, maxFee uint64) (string, error) {
+	return t.swap(ctx, "reverse swap", chain, amount, func(b *boltz.Service) (string, string, error) {
+		return b.CreateReverseSwap(ctx, chain, amount, address, maxFee)
+	})
+}
+
+// swap creates a swap of kind ("swap" or "reverse swap"). The swap completes
+// later, so the event only tells that it was created.
+func (t *Launcher) swap(ctx context.Context, kind string, chain string, amount uint64, create func(b *boltz.Service) (string, string, error)) (string, error) {
+	var output string
+	err := t.inNetworkDir(func() error {
+		b, err := t.getBoltz()
+		if err != nil {
+			return err
+		}
+		var id string
+		id, output, err = create(b)
+		e := Event{
+			Type:    EventSwap,
+			Service: "boltz",
+			Outcome: outcome(err),
+			Message: fmt.Sprintf("Created %s %s of %s %s", kind, id, formatCoins(amount), strings.ToUpper(chain)),
+		}
+		if err != nil {
+			e.Message = fmt.Sprintf("Failed to create %s of %s %s: %s", kind, formatCoins(amount), strings.ToUpper(chain), err)
+		}
+		t.emit(e)
+		return err
+	})
+	return output, err
+}
+
+// SwapRefund reports the refund of a failed swap in
+type SwapRefund struct {
+	Swap        *boltz.Swap `json:"swap"`
+	BlockHeight uint32      `json:"blockHeight"`
+	// BlocksLeft is the number of blocks until boltz refunds the swap
+	BlocksLeft uint32 `json:"blocksLeft"`
+}
+
+// GetSwapRefund looks up the refund state of swap id. boltz refunds failed
+// swaps to the lnd wallet by itself once their timeout block height is
+// reached.
+func (t *Launcher) GetSwapRefund(ctx context.Context, chain string, id string) (*SwapRefund, error) {
+	var refund SwapRefund
+	err := t.inNetworkDir(func() error {
+		b, err := t.getBoltz()
+		if err != nil {
+			return err
+		}
+		info, err := b.GetSwapInfo(ctx, chain, id)
+		if err != nil {
+			return err
+		}
+		if info.Swap == nil {
+			return fmt.Errorf("%s is not a swap in, only swaps in are refunded", id)
+		}
+		chainInfo, err := b.GetChainInfo(ctx, chain)
+		if err != nil {
+			return err
+		}
+		refund.Swap = info.Swap
+		refund.BlockHeight = chainInfo.BlockHeight
+		if info.Swap.TimeoutBlockHeight > chainInfo.BlockHeight {
+			refund.BlocksLeft = info.Swap.TimeoutBlockHeight - chainInfo.BlockHeight
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
@@ -8,4 +8,9 @@ import (
 func (t *Launcher) Update(ctx context.Context) {
 	// TODO update
 	fmt.Println("to be implemented")
+	t.emit(Event{
+		Type:    EventUpdate,
+		Outcome: "skipped",
+		Message: "Update is not implemented yet",
+	})
 }
@@ -0,0 +1,18 @@
+package core
+
+import (
+	"errors"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/webui"
+)
+
+// GetWebuiUrl returns the local webui URL with the access token
+func (t *Launcher) GetWebuiUrl() (string, error) {
+	s, err := t.GetService("webui")
+	if err != nil {
+		return "", err
+	}
+	if s.IsDisabled() {
+		return "", errors.New("webui is disabled, enable it with --webui.disabled=false")
+	}
+	return s.(*webui.Service).GetUrl(), nil
+}
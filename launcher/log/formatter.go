@@ -32,7 +32,7 @@ func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
 		entry.Time.Format("2006-01-02 15:04:05.000"),
 		strings.ToUpper(entry.Level.String()),
 		name,
-		entry.Message,
+		Redact(entry.Message),
 	))
 
 	return b.Bytes(), nil
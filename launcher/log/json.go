@@ -0,0 +1,50 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+type JSONFormatter struct {
+}
+
+func (f *JSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields)
+	for k, v := range entry.Data {
+		switch v := v.(type) {
+		case error:
+			data[k] = Redact(v.Error())
+		case string:
+			data[k] = Redact(v)
+		default:
+			data[k] = v
+		}
+	}
+
+	data["time"] = entry.Time.Format("2006-01-02T15:04:05.000Z07:00")
+	data["level"] = entry.Level.String()
+	data["msg"] = Redact(entry.Message)
+
+	// "name" is the logger name which is "service.<name>" for services
+	if name, ok := data["name"].(string); ok {
+		data["logger"] = name
+		if strings.HasPrefix(name, "service.") {
+			data["service"] = strings.TrimPrefix(name, "service.")
+		}
+		delete(data, "name")
+	}
+
+	j, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal log entry: %w", err)
+	}
+
+	if entry.Buffer != nil {
+		entry.Buffer.Write(j)
+		entry.Buffer.WriteByte('\n')
+		return entry.Buffer.Bytes(), nil
+	}
+	return append(j, '\n'), nil
+}
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const Redacted = "***"
+
+var (
+	// secretKeyPattern matches the names of keys holding secret values like
+	// "password", "rpcpass", "CEX_API_SECRET" or "adminToken"
+	secretKeyPattern = `[A-Za-z0-9_.-]*(?i:password|passwd|rpcpass|rpcauth|secret|token|mnemonic|seed|api_key|apikey)[A-Za-z0-9_.-]*`
+
+	// "key": "value" or "key": [...] in JSON text
+	reJsonSecret = regexp.MustCompile(`("` + secretKeyPattern + `"\s*:\s*)("(?:[^"\\]|\\.)*"|\[[^\]]*\])`)
+	// KEY=value in environment variables and --key=value in command-line options
+	reKvSecret = regexp.MustCompile(`(` + secretKeyPattern + `=)("(?:[^"\\]|\\.)*"|[^\s"',]+)`)
+
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// RegisterSecret makes every later occurrence of value in log messages
+// redacted regardless of the surrounding format.
+func RegisterSecret(value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < 4 {
+		// too short to be redacted without destroying unrelated text
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, s := range secrets {
+		if s == value {
+			return
+		}
+	}
+	secrets = append(secrets, value)
+	// replace longer secrets first in case one secret contains another
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+}
+
+// Redact replaces the values of known secret keys and registered secrets
+// in s with "***".
+func Redact(s string) string {
+	s = reJsonSecret.ReplaceAllString(s, `${1}"`+Redacted+`"`)
+	s = reKvSecret.ReplaceAllString(s, "${1}"+Redacted)
+
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
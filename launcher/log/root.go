@@ -1,8 +1,10 @@
 package log
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"io"
+	"strings"
 )
 
 var (
@@ -16,6 +18,27 @@ func SetOutput(output io.Writer) {
 	rootLogger.SetOutput(output)
 }
 
+func SetLevel(level string) error {
+	l, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	rootLogger.SetLevel(l)
+	return nil
+}
+
+func SetFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "text":
+		rootLogger.SetFormatter(&Formatter{})
+	case "json":
+		rootLogger.SetFormatter(&JSONFormatter{})
+	default:
+		return fmt.Errorf("unsupported log format: %s", format)
+	}
+	return nil
+}
+
 func NewLogger(name string) *logrus.Entry {
 	return logrus.NewEntry(rootLogger).WithField("name", name)
 }
@@ -0,0 +1,259 @@
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxSize    = 10 // megabytes
+	DefaultMaxAge     = 30 // days
+	DefaultMaxBackups = 5
+
+	backupTimeFormat = "20060102-150405.000"
+)
+
+// RotatingFile is an io.WriteCloser which rotates the underlying file when it
+// grows beyond MaxSize or when it becomes older than MaxAge. Rotated files are
+// renamed to <name>-<timestamp><ext> (optionally gzip compressed) and only the
+// latest MaxBackups of them are kept.
+type RotatingFile struct {
+	Filename   string
+	MaxSize    int // megabytes, 0 disables size based rotation
+	MaxAge     int // days, 0 disables age based rotation
+	MaxBackups int // 0 keeps all rotated files
+	Compress   bool
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func OpenRotatingFile(filename string) (*RotatingFile, error) {
+	f := &RotatingFile{
+		Filename:   filename,
+		MaxSize:    DefaultMaxSize,
+		MaxAge:     DefaultMaxAge,
+		MaxBackups: DefaultMaxBackups,
+		Compress:   true,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (t *RotatingFile) open() error {
+	file, err := os.OpenFile(t.Filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	t.file = file
+	t.size = info.Size()
+	t.openedAt = t.loadStartTime()
+	return nil
+}
+
+// startFile keeps the time of the first write into the current file. The
+// modification time is useless for age based rotation as every run appends.
+func (t *RotatingFile) startFile() string {
+	return t.Filename + ".start"
+}
+
+func (t *RotatingFile) loadStartTime() time.Time {
+	if t.size > 0 {
+		if data, err := ioutil.ReadFile(t.startFile()); err == nil {
+			if start, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data))); err == nil {
+				return start
+			}
+		}
+	}
+	// a new file, or one written before the start time was recorded
+	now := time.Now()
+	_ = ioutil.WriteFile(t.startFile(), []byte(now.UTC().Format(time.RFC3339)+"\n"), 0644)
+	return now
+}
+
+func (t *RotatingFile) shouldRotate(n int) bool {
+	if t.size == 0 {
+		return false
+	}
+	if t.MaxSize > 0 && t.size+int64(n) > int64(t.MaxSize)*1024*1024 {
+		return true
+	}
+	if t.MaxAge > 0 && time.Since(t.openedAt) > time.Duration(t.MaxAge)*24*time.Hour {
+		return true
+	}
+	return false
+}
+
+func (t *RotatingFile) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if t.shouldRotate(len(p)) {
+		if err := t.rotate(); err != nil {
+			return 0, fmt.Errorf("rotate %s: %w", t.Filename, err)
+		}
+	}
+
+	n, err := t.file.Write(p)
+	t.size += int64(n)
+	return n, err
+}
+
+// Rotate forces the current file to be rotated.
+func (t *RotatingFile) Rotate() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rotate()
+}
+
+func (t *RotatingFile) backupName(now time.Time) string {
+	ext := filepath.Ext(t.Filename)
+	prefix := strings.TrimSuffix(t.Filename, ext)
+	return fmt.Sprintf("%s-%s%s", prefix, now.Format(backupTimeFormat), ext)
+}
+
+func (t *RotatingFile) rotate() error {
+	if t.file != nil {
+		if err := t.file.Close(); err != nil {
+			return err
+		}
+		t.file = nil
+	}
+
+	backup := t.backupName(time.Now())
+	if err := os.Rename(t.Filename, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := t.open(); err != nil {
+		return err
+	}
+
+	if t.Compress {
+		if err := compressFile(backup); err != nil {
+			return fmt.Errorf("compress %s: %w", backup, err)
+		}
+	}
+
+	return t.cleanup()
+}
+
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	w := gzip.NewWriter(dst)
+	if _, err := io.Copy(w, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+// backups returns the rotated files of t.Filename ordered from newest to oldest
+func (t *RotatingFile) backups() ([]string, error) {
+	ext := filepath.Ext(t.Filename)
+	prefix := strings.TrimSuffix(filepath.Base(t.Filename), ext) + "-"
+
+	entries, err := ioutil.ReadDir(filepath.Dir(t.Filename))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimPrefix(name, prefix)
+		ts = strings.TrimSuffix(ts, ".gz")
+		ts = strings.TrimSuffix(ts, ext)
+		if _, err := time.Parse(backupTimeFormat, ts); err != nil {
+			continue
+		}
+		result = append(result, filepath.Join(filepath.Dir(t.Filename), name))
+	}
+
+	// the timestamp format sorts lexically
+	sort.Sort(sort.Reverse(sort.StringSlice(result)))
+	return result, nil
+}
+
+func (t *RotatingFile) cleanup() error {
+	if t.MaxBackups <= 0 && t.MaxAge <= 0 {
+		return nil
+	}
+
+	backups, err := t.backups()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-time.Duration(t.MaxAge) * 24 * time.Hour)
+
+	for i, name := range backups {
+		remove := t.MaxBackups > 0 && i >= t.MaxBackups
+		if !remove && t.MaxAge > 0 {
+			if info, err := os.Stat(name); err == nil && info.ModTime().Before(cutoff) {
+				remove = true
+			}
+		}
+		if remove {
+			if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *RotatingFile) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
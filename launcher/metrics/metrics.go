@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Type string
+
+const (
+	Gauge   Type = "gauge"
+	Counter Type = "counter"
+)
+
+// Metric is a family of samples sharing the same name, help text and label
+// names. It is rendered in the Prometheus text exposition format.
+type Metric struct {
+	Name   string
+	Help   string
+	Type   Type
+	Labels []string
+
+	mu      sync.Mutex
+	samples map[string]*sample
+}
+
+type sample struct {
+	labelValues []string
+	value       float64
+}
+
+func (t *Metric) key(labelValues []string) string {
+	if len(labelValues) != len(t.Labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", t.Name, len(t.Labels), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\x00")
+}
+
+func (t *Metric) get(labelValues []string) *sample {
+	k := t.key(labelValues)
+	s, ok := t.samples[k]
+	if !ok {
+		s = &sample{labelValues: append([]string{}, labelValues...)}
+		t.samples[k] = s
+	}
+	return s
+}
+
+func (t *Metric) Set(value float64, labelValues ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.get(labelValues).value = value
+}
+
+func (t *Metric) Add(delta float64, labelValues ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.get(labelValues).value += delta
+}
+
+func (t *Metric) Inc(labelValues ...string) {
+	t.Add(1, labelValues...)
+}
+
+// Delete removes the sample of labelValues, e.g. when a service is disabled
+func (t *Metric) Delete(labelValues ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.samples, t.key(labelValues))
+}
+
+func escapeLabelValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return v
+}
+
+func (t *Metric) writeTo(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", t.Name, t.Help, t.Name, t.Type); err != nil {
+		return err
+	}
+
+	var keys []string
+	for k := range t.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := t.samples[k]
+		var b strings.Builder
+		b.WriteString(t.Name)
+		if len(t.Labels) > 0 {
+			b.WriteString("{")
+			for i, name := range t.Labels {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				b.WriteString(fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(s.labelValues[i])))
+			}
+			b.WriteString("}")
+		}
+		b.WriteString(" ")
+		b.WriteString(strconv.FormatFloat(s.value, 'g', -1, 64))
+		b.WriteString("\n")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type Registry struct {
+	mu      sync.Mutex
+	metrics []*Metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (t *Registry) register(name string, help string, type_ Type, labels []string) *Metric {
+	m := &Metric{
+		Name:    name,
+		Help:    help,
+		Type:    type_,
+		Labels:  labels,
+		samples: make(map[string]*sample),
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.metrics = append(t.metrics, m)
+	return m
+}
+
+func (t *Registry) NewGauge(name string, help string, labels ...string) *Metric {
+	return t.register(name, help, Gauge, labels)
+}
+
+func (t *Registry) NewCounter(name string, help string, labels ...string) *Metric {
+	return t.register(name, help, Counter, labels)
+}
+
+func (t *Registry) Write(w io.Writer) error {
+	t.mu.Lock()
+	metrics := append([]*Metric{}, t.metrics...)
+	t.mu.Unlock()
+
+	for _, m := range metrics {
+		if err := m.writeTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = t.Write(w)
+	})
+}
@@ -0,0 +1,85 @@
+// Package nat discovers the public IP address of the node behind a NAT. It
+// only queries the gateway for its address and requests no port mappings, so
+// the p2p ports still have to be forwarded on the router.
+package nat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	MethodUpnp   = "upnp"
+	MethodNatPmp = "nat-pmp"
+	MethodStun   = "stun"
+)
+
+// StunServers are queried in order when the gateway doesn't support NAT-PMP
+var StunServers = []string{
+	"stun.l.google.com:19302",
+	"stun1.l.google.com:19302",
+	"stun.cloudflare.com:3478",
+}
+
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublic tells whether ip is routable on the internet
+func IsPublic(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		if ip4[0] == 10 || (ip4[0] == 172 && ip4[1]&0xf0 == 16) || (ip4[0] == 192 && ip4[1] == 168) {
+			return false
+		}
+		if cgnat.Contains(ip4) {
+			return false
+		}
+	}
+	return true
+}
+
+// Discover finds the public IP address through UPnP or NAT-PMP from the LAN
+// gateway or else through STUN. It returns the method which found the
+// address.
+func Discover(ctx context.Context) (net.IP, string, error) {
+	var errs []string
+
+	// the gateway address is not public behind another (carrier-grade) NAT
+	check := func(method string, ip net.IP, err error) bool {
+		if err == nil && !IsPublic(ip) {
+			err = fmt.Errorf("gateway address %s is not public", ip)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", method, err))
+			return false
+		}
+		return true
+	}
+
+	ip, err := UpnpExternalAddress(ctx)
+	if check(MethodUpnp, ip, err) {
+		return ip, MethodUpnp, nil
+	}
+
+	gateway, err := DefaultGateway()
+	if err == nil {
+		ip, err = ExternalAddress(ctx, gateway)
+	}
+	if check(MethodNatPmp, ip, err) {
+		return ip, MethodNatPmp, nil
+	}
+
+	for _, server := range StunServers {
+		ip, err := StunQuery(ctx, server)
+		if err == nil {
+			return ip, MethodStun, nil
+		}
+		errs = append(errs, fmt.Sprintf("stun %s: %s", server, err))
+	}
+
+	return nil, "", errors.New(strings.Join(errs, "; "))
+}
@@ -0,0 +1,84 @@
+package nat
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const natPmpPort = 5351
+
+// DefaultGateway reads the IPv4 default gateway from /proc/net/route
+func DefaultGateway() (net.IP, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, fmt.Errorf("read routes: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Iface Destination Gateway Flags ...
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		b, err := hex.DecodeString(fields[2])
+		if err != nil || len(b) != 4 {
+			continue
+		}
+		// the addresses are in host (little endian) byte order
+		return net.IPv4(b[3], b[2], b[1], b[0]), nil
+	}
+	return nil, errors.New("no default gateway")
+}
+
+// ExternalAddress asks the NAT-PMP (RFC 6886) gateway for its external
+// address
+func ExternalAddress(ctx context.Context, gateway net.IP) (net.IP, error) {
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "udp4", fmt.Sprintf("%s:%d", gateway, natPmpPort))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// version 0, opcode 0 (external address)
+	request := []byte{0, 0}
+	response := make([]byte, 16)
+
+	// retransmit with doubling timeouts like the RFC suggests
+	timeout := 250 * time.Millisecond
+	for i := 0; i < 4; i++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if _, err := conn.Write(request); err != nil {
+			return nil, err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(timeout))
+		n, err := conn.Read(response)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				timeout *= 2
+				continue
+			}
+			return nil, err
+		}
+		if n < 12 || response[0] != 0 || response[1] != 128 {
+			return nil, errors.New("invalid response")
+		}
+		if code := binary.BigEndian.Uint16(response[2:4]); code != 0 {
+			return nil, fmt.Errorf("result code %d", code)
+		}
+		return net.IPv4(response[8], response[9], response[10], response[11]), nil
+	}
+	return nil, errors.New("no response")
+}
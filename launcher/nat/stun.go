@@ -0,0 +1,106 @@
+package nat
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"time"
+)
+
+const (
+	stunMagicCookie      = 0x2112A442
+	stunBindingRequest   = 0x0001
+	stunBindingSuccess   = 0x0101
+	stunMappedAddress    = 0x0001
+	stunXorMappedAddress = 0x0020
+	stunTimeout          = 3 * time.Second
+)
+
+// StunQuery sends a STUN (RFC 5389) binding request to server and returns
+// the mapped address the server saw
+func StunQuery(ctx context.Context, server string) (net.IP, error) {
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "udp4", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	txId := make([]byte, 12)
+	if _, err := rand.Read(txId); err != nil {
+		return nil, err
+	}
+	request := make([]byte, 20)
+	binary.BigEndian.PutUint16(request[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint16(request[2:4], 0)
+	binary.BigEndian.PutUint32(request[4:8], stunMagicCookie)
+	copy(request[8:20], txId)
+
+	deadline := time.Now().Add(stunTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+	response := make([]byte, 512)
+	n, err := conn.Read(response)
+	if err != nil {
+		return nil, err
+	}
+	return parseStunResponse(response[:n], txId)
+}
+
+func parseStunResponse(b []byte, txId []byte) (net.IP, error) {
+	if len(b) < 20 {
+		return nil, errors.New("short response")
+	}
+	if binary.BigEndian.Uint16(b[0:2]) != stunBindingSuccess {
+		return nil, errors.New("not a binding success response")
+	}
+	if binary.BigEndian.Uint32(b[4:8]) != stunMagicCookie || !bytes.Equal(b[8:20], txId) {
+		return nil, errors.New("unexpected transaction")
+	}
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	attrs := b[20:]
+	if len(attrs) < length {
+		return nil, errors.New("truncated response")
+	}
+	attrs = attrs[:length]
+
+	var mapped net.IP
+	for len(attrs) >= 4 {
+		typ := binary.BigEndian.Uint16(attrs[0:2])
+		l := int(binary.BigEndian.Uint16(attrs[2:4]))
+		if len(attrs) < 4+l {
+			break
+		}
+		value := attrs[4 : 4+l]
+		// family 0x01 is IPv4: reserved, family, port, address
+		if len(value) >= 8 && value[1] == 0x01 {
+			ip := net.IPv4(value[4], value[5], value[6], value[7]).To4()
+			switch typ {
+			case stunXorMappedAddress:
+				cookie := make([]byte, 4)
+				binary.BigEndian.PutUint32(cookie, stunMagicCookie)
+				for i := range ip {
+					ip[i] ^= cookie[i]
+				}
+				return ip, nil
+			case stunMappedAddress:
+				mapped = ip
+			}
+		}
+		// attributes are padded to 4 bytes
+		attrs = attrs[4+(l+3)/4*4:]
+	}
+	if mapped != nil {
+		return mapped, nil
+	}
+	return nil, errors.New("no mapped address")
+}
@@ -0,0 +1,176 @@
+package nat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ssdpAddress = "239.255.255.250:1900"
+	ssdpTimeout = 2 * time.Second
+)
+
+var upnpServices = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANIPConnection:2",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+type upnpDevice struct {
+	Services []upnpService `xml:"serviceList>service"`
+	Devices  []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpRoot struct {
+	Device upnpDevice `xml:"device"`
+}
+
+func (t upnpDevice) findService() *upnpService {
+	for _, s := range t.Services {
+		for _, typ := range upnpServices {
+			if s.ServiceType == typ {
+				return &s
+			}
+		}
+	}
+	for _, d := range t.Devices {
+		if s := d.findService(); s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// UpnpExternalAddress asks the UPnP internet gateway device of the LAN for its
+// external address
+func UpnpExternalAddress(ctx context.Context) (net.IP, error) {
+	location, err := ssdpSearch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := http.Client{Timeout: 3 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get device description: %w", err)
+	}
+	defer resp.Body.Close()
+	var root upnpRoot
+	if err := xml.NewDecoder(resp.Body).Decode(&root); err != nil {
+		return nil, fmt.Errorf("decode device description: %w", err)
+	}
+	s := root.Device.findService()
+	if s == nil {
+		return nil, errors.New("no WAN connection service")
+	}
+
+	base, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	control, err := base.Parse(s.ControlURL)
+	if err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf(`<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">
+<s:Body><u:GetExternalIPAddress xmlns:u="%s"/></s:Body>
+</s:Envelope>`, s.ServiceType)
+	req, err = http.NewRequestWithContext(ctx, "POST", control.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#GetExternalIPAddress"`, s.ServiceType))
+	resp, err = client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetExternalIPAddress: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GetExternalIPAddress: %s", resp.Status)
+	}
+
+	var result struct {
+		Address string `xml:"Body>GetExternalIPAddressResponse>NewExternalIPAddress"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode GetExternalIPAddress response: %w", err)
+	}
+	ip := net.ParseIP(strings.TrimSpace(result.Address))
+	if ip == nil {
+		return nil, fmt.Errorf("invalid external address: %q", result.Address)
+	}
+	return ip, nil
+}
+
+// ssdpSearch multicasts an M-SEARCH for internet gateway devices and returns
+// the description location of the first one answering
+func ssdpSearch(ctx context.Context) (string, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	dst, err := net.ResolveUDPAddr("udp4", ssdpAddress)
+	if err != nil {
+		return "", err
+	}
+
+	request := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddress + "\r\n" +
+		"ST: urn:schemas-upnp-org:device:InternetGatewayDevice:1\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n\r\n"
+	if _, err := conn.WriteTo([]byte(request), dst); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(ssdpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+
+	b := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFrom(b)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return "", errors.New("no gateway device")
+			}
+			return "", err
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b[:n])), nil)
+		if err != nil {
+			continue
+		}
+		_, _ = ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if location := resp.Header.Get("Location"); location != "" {
+			return location, nil
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package secrets stores sensitive configuration values encrypted at rest
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/utils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const keySize = 32
+
+// Store keeps every secret AES-256-GCM encrypted in a <name>.enc file of Dir.
+// The key lives in KeyFile outside of the network directory, so copies and
+// backups of the network directory don't expose the secrets.
+type Store struct {
+	Dir     string
+	KeyFile string
+}
+
+func NewStore(dir string, keyFile string) *Store {
+	return &Store{
+		Dir:     dir,
+		KeyFile: keyFile,
+	}
+}
+
+func (t *Store) path(name string) string {
+	return filepath.Join(t.Dir, name+".enc")
+}
+
+// loadOrCreateKey reads the key from KeyFile and generates it on first use
+func (t *Store) loadOrCreateKey() ([]byte, error) {
+	data, err := ioutil.ReadFile(t.KeyFile)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("invalid secret key file %s", t.KeyFile)
+		}
+		return key, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.KeyFile), 0700); err != nil {
+		return nil, err
+	}
+	if err := utils.WriteFileSecure(t.KeyFile, []byte(hex.EncodeToString(key)+"\n")); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (t *Store) gcm() (cipher.AEAD, error) {
+	key, err := t.loadOrCreateKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Get decrypts the secret name. It returns an empty string when the secret
+// doesn't exist.
+func (t *Store) Get(name string) (string, error) {
+	data, err := ioutil.ReadFile(t.path(name))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return "", fmt.Errorf("decode secret %s: %w", name, err)
+	}
+
+	gcm, err := t.gcm()
+	if err != nil {
+		return "", err
+	}
+	if len(b) < gcm.NonceSize() {
+		return "", fmt.Errorf("decrypt secret %s: truncated", name)
+	}
+	nonce, ciphertext := b[:gcm.NonceSize()], b[gcm.NonceSize():]
+	// the name is authenticated so that secret files can't be swapped
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("decrypt secret %s: %w", name, err)
+	}
+	return string(plaintext), nil
+}
+
+// Put encrypts value into the secret name
+func (t *Store) Put(name string, value string) error {
+	if name == "" {
+		return errors.New("empty secret name")
+	}
+
+	gcm, err := t.gcm()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	b := gcm.Seal(nonce, nonce, []byte(value), []byte(name))
+
+	if err := os.MkdirAll(t.Dir, 0700); err != nil {
+		return err
+	}
+	return utils.WriteFileSecure(t.path(name), []byte(base64.StdEncoding.EncodeToString(b)+"\n"))
+}
+
+// Delete removes the secret name
+func (t *Store) Delete(name string) error {
+	if err := os.Remove(t.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
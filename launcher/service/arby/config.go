@@ -1,9 +1,14 @@
 package arby
 
 import (
+	"errors"
+	"fmt"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/opendexd"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 type BaseConfig = base.Config
@@ -20,9 +25,9 @@ type Config struct {
 	TestCentralizedBaseassetBalance  string `usage:"Test centralized base asset balance"`
 	TestCentralizedQuoteassetBalance string `usage:"Test centralized quote asset balance"`
 	Cex                              string `usage:"Centralized Exchange"`
-	CexApiKey                        string `usage:"CEX API key"`
-	CexApiSecret                     string `usage:"CEX API secret"`
-	Margin                           string `usage:"Trade margin"`
+	CexApiKey                        string `usage:"CEX API key (stored encrypted, omit to use the stored one)"`
+	CexApiSecret                     string `usage:"CEX API secret (stored encrypted, omit to use the stored one)"`
+	Margin                           string `usage:"Trade margin (0-0.5)"`
 }
 
 func (t *Service) GetDefaultConfig() interface{} {
@@ -33,23 +38,98 @@ func (t *Service) GetDefaultConfig() interface{} {
 	} else {
 		image = "opendexnetwork/arby:latest"
 	}
+	// an instance (e.g. arby-eth-btc) is enabled and trades its pair by default
+	disabled := true
+	var baseAsset, quoteAsset string
+	if instance := strings.TrimPrefix(t.Name, "arby-"); instance != t.Name {
+		disabled = false
+		if parts := strings.Split(instance, "-"); len(parts) == 2 {
+			baseAsset = strings.ToUpper(parts[0])
+			quoteAsset = strings.ToUpper(parts[1])
+		}
+	}
 	return &Config{
 		BaseConfig: BaseConfig{
 			Image:    t.Base.GetBranchImage(image),
-			Disabled: true,
+			Disabled: disabled,
 			Dir:      filepath.Join(t.Context.GetDataDir(), t.Name),
 		},
 		LiveCex:                          true,
 		TestMode:                         true,
-		BaseAsset:                        "",
-		QuoteAsset:                       "",
+		BaseAsset:                        baseAsset,
+		QuoteAsset:                       quoteAsset,
 		CexBaseAsset:                     "",
 		CexQuoteAsset:                    "",
 		TestCentralizedBaseassetBalance:  "",
 		TestCentralizedQuoteassetBalance: "",
 		Cex:                              "binance",
-		CexApiKey:                        "123",
-		CexApiSecret:                     "abc",
+		CexApiKey:                        "",
+		CexApiSecret:                     "",
 		Margin:                           "0.04",
 	}
 }
+
+const (
+	MinMargin = 0
+	MaxMargin = 0.5
+
+	// placeholder CEX credentials arby is started with in test mode
+	placeholderApiKey    = "123"
+	placeholderApiSecret = "abc"
+)
+
+// Cexes are the centralized exchanges arby supports
+var Cexes = []string{"binance", "kraken"}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isPlaceholder(value string, placeholder string) bool {
+	return value == "" || value == placeholder
+}
+
+// validate checks the options of an enabled arby. apiKey and apiSecret are
+// the effective credentials including the stored ones.
+func (t *Service) validate(c *Config, apiKey string, apiSecret string) error {
+	cex := strings.ToLower(c.Cex)
+	if !contains(Cexes, cex) {
+		return fmt.Errorf("unsupported CEX %q (supported: %s)", c.Cex, strings.Join(Cexes, ", "))
+	}
+
+	if c.BaseAsset == "" || c.QuoteAsset == "" {
+		return errors.New("base asset and quote asset are required")
+	}
+	pair := fmt.Sprintf("%s/%s", strings.ToUpper(c.BaseAsset), strings.ToUpper(c.QuoteAsset))
+	if !contains(opendexd.Pairs, pair) {
+		return fmt.Errorf("unsupported pair %s (supported: %s)", pair, strings.Join(opendexd.Pairs, ", "))
+	}
+
+	margin, err := strconv.ParseFloat(c.Margin, 64)
+	if err != nil {
+		return fmt.Errorf("invalid margin %q", c.Margin)
+	}
+	if margin <= MinMargin || margin > MaxMargin {
+		return fmt.Errorf("margin %s out of range (%v, %v]", c.Margin, MinMargin, MaxMargin)
+	}
+
+	for _, balance := range []string{c.TestCentralizedBaseassetBalance, c.TestCentralizedQuoteassetBalance} {
+		if balance == "" {
+			continue
+		}
+		if v, err := strconv.ParseFloat(balance, 64); err != nil || v < 0 {
+			return fmt.Errorf("invalid test centralized balance %q", balance)
+		}
+	}
+
+	if !c.TestMode && (isPlaceholder(apiKey, placeholderApiKey) || isPlaceholder(apiSecret, placeholderApiSecret)) {
+		return errors.New("refusing to trade on the CEX with placeholder API credentials, set the CEX API key and secret or enable the test mode")
+	}
+
+	return nil
+}
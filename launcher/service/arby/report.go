@@ -0,0 +1,217 @@
+package arby
+
+import (
+	"context"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/opendexd"
+	"math"
+	"regexp"
+	"strings"
+)
+
+const (
+	Connected    = "connected"
+	Disconnected = "disconnected"
+	Unknown      = "unknown"
+
+	// reportLogLines is how many latest log lines are searched for CEX errors
+	reportLogLines = "1000"
+	// statusLogLines is the smaller window GetStatus searches on every poll
+	statusLogLines = "100"
+
+	// tcpEstablished is the ESTABLISHED state in /proc/net/tcp
+	tcpEstablished = "01"
+)
+
+// reCexError matches the ccxt exceptions which tell that arby cannot reach or
+// authenticate with the CEX. arby logs them with their class name.
+var reCexError = regexp.MustCompile(`\b(AuthenticationError|PermissionDenied|AccountSuspended|NetworkError|ExchangeNotAvailable|RequestTimeout|DDoSProtection|InvalidNonce)\b`)
+
+// Report is the connection state and the open orders of arby next to the
+// opendexd trades of its pair. arby doesn't report its own fills, so the
+// position and profit and loss summarize all opendexd trades of the pair,
+// whether arby made them or not, and leave out the CEX leg. They are not the
+// PnL of the arbitrage. The PnL is in the quote currency.
+type Report struct {
+	Pair string `json:"pair"`
+	Cex  string `json:"cex"`
+	// Opendexd is connected while arby holds a connection to the opendexd
+	// RPC port
+	Opendexd string `json:"opendexd"`
+	// CexConnection compares the latest ccxt connection or authentication
+	// error in the arby logs with the latest CEX line after it
+	CexConnection string           `json:"cexConnection"`
+	OpenOrders    []opendexd.Order `json:"openOrders"`
+	// Trades are the latest opendexd trades of the pair, limited by GetReport
+	Trades []opendexd.Trade `json:"trades"`
+	// OpendexdPosition is the net base currency amount bought in all
+	// opendexd trades of the pair
+	OpendexdPosition      float64 `json:"opendexdPosition"`
+	OpendexdRealizedPnl   float64 `json:"opendexdRealizedPnl"`
+	OpendexdUnrealizedPnl float64 `json:"opendexdUnrealizedPnl"`
+}
+
+// reErrorLine matches the log lines of failures which are not ccxt
+// connection errors, e.g. a rejected order
+var reErrorLine = regexp.MustCompile(`(?i)\b(error|failed|exception)\b`)
+
+// parseCexState compares the latest ccxt error in the chronological arby log
+// lines with the latest line about cex which is no error. arby recovered when
+// it logged such a line after the error.
+func parseCexState(lines []string, cex string) string {
+	state := Unknown
+	for _, line := range lines {
+		if reCexError.MatchString(line) {
+			state = Disconnected
+		} else if cex != "" && strings.Contains(strings.ToLower(line), cex) && !reErrorLine.MatchString(line) {
+			state = Connected
+		}
+	}
+	return state
+}
+
+// hasConnection tells whether /proc/net/tcp or /proc/net/tcp6 lists an
+// established connection to the remote port
+func hasConnection(procNetTcp string, port uint16) bool {
+	suffix := fmt.Sprintf(":%04X", port)
+	for _, line := range strings.Split(procNetTcp, "\n") {
+		// sl local_address rem_address st ...
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] == "sl" {
+			continue
+		}
+		if strings.HasSuffix(fields[2], suffix) && fields[3] == tcpEstablished {
+			return true
+		}
+	}
+	return false
+}
+
+// computePnl calculates the average cost profit and loss of the opendex
+// trades of pair. The open position is valued at the latest trade price.
+func computePnl(trades []opendexd.Trade, pair string) (position float64, realized float64, unrealized float64) {
+	var cost float64 // average price of the open position
+	var last float64
+	for _, trade := range trades {
+		if trade.PairId != pair {
+			continue
+		}
+		quantity := float64(trade.Quantity) / 1e8
+		if trade.Side == "sell" {
+			quantity = -quantity
+		}
+		last = trade.Price
+
+		if position == 0 || (position > 0) == (quantity > 0) {
+			// opening or increasing the position
+			cost = (cost*math.Abs(position) + trade.Price*math.Abs(quantity)) / (math.Abs(position) + math.Abs(quantity))
+			position += quantity
+			continue
+		}
+
+		// closing (and maybe reversing) the position
+		closed := math.Min(math.Abs(quantity), math.Abs(position))
+		if position > 0 {
+			realized += closed * (trade.Price - cost)
+		} else {
+			realized += closed * (cost - trade.Price)
+		}
+		position += quantity
+		if math.Abs(position) < 1e-12 {
+			position = 0
+		} else if math.Abs(quantity) > closed {
+			// reversed at the trade price
+			cost = trade.Price
+		}
+	}
+	unrealized = position * (last - cost)
+	if unrealized == 0 {
+		// no negative zero
+		unrealized = 0
+	}
+	return position, realized, unrealized
+}
+
+// getOpendexdState checks the connections of the arby container for one to
+// the opendexd RPC port
+func (t *Service) getOpendexdState(ctx context.Context) (string, error) {
+	od, err := t.getOpendexd()
+	if err != nil {
+		return "", err
+	}
+	params, err := od.GetRpcParams()
+	if err != nil {
+		return "", err
+	}
+	output, err := t.Exec(ctx, "sh", "-c", "cat /proc/net/tcp /proc/net/tcp6 2>/dev/null; true")
+	if err != nil {
+		return "", fmt.Errorf("list connections: %w", err)
+	}
+	if hasConnection(output, params.(OpendexdRpcParams).Port) {
+		return Connected, nil
+	}
+	return Disconnected, nil
+}
+
+// getConnections returns the opendexd connection state and the CEX state
+// found in the latest tail log lines
+func (t *Service) getConnections(ctx context.Context, tail string) (string, string, error) {
+	opendexdState, err := t.getOpendexdState(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	lines, err := t.GetLogs(ctx, "", tail)
+	if err != nil {
+		return "", "", fmt.Errorf("get logs: %w", err)
+	}
+	return opendexdState, parseCexState(lines, t.Cex), nil
+}
+
+// GetReport collects the connection states and open orders of arby and the
+// opendexd trades of its pair. The PnL covers the whole trade history of the
+// pair, only the listed trades are limited to the latest limit (0 for all).
+func (t *Service) GetReport(ctx context.Context, limit int) (*Report, error) {
+	od, err := t.getOpendexd()
+	if err != nil {
+		return nil, err
+	}
+
+	report := Report{
+		Pair: t.Pair,
+		Cex:  t.Cex,
+	}
+
+	report.Opendexd, report.CexConnection, err = t.getConnections(ctx, reportLogLines)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := od.ListOwnOrders(ctx, t.Pair)
+	if err != nil {
+		return nil, fmt.Errorf("list orders: %w", err)
+	}
+	report.OpenOrders = orders
+
+	trades, err := od.TradeHistory(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("trade history: %w", err)
+	}
+	report.Trades = []opendexd.Trade{}
+	for _, trade := range trades {
+		if trade.PairId == t.Pair {
+			report.Trades = append(report.Trades, trade)
+		}
+	}
+	report.OpendexdPosition, report.OpendexdRealizedPnl, report.OpendexdUnrealizedPnl = computePnl(report.Trades, t.Pair)
+	if limit > 0 && len(report.Trades) > limit {
+		report.Trades = report.Trades[len(report.Trades)-limit:]
+	}
+
+	return &report, nil
+}
+
+// QuoteAsset returns the currency the PnL is in
+func (t *Report) QuoteAsset() string {
+	parts := strings.Split(t.Pair, "/")
+	return parts[len(parts)-1]
+}
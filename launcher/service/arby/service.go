@@ -7,6 +7,7 @@ import (
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
 	_opendexd "github.com/opendexnetwork/opendex-docker/launcher/service/opendexd"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"strings"
 )
 
 type Base = base.Service
@@ -15,6 +16,12 @@ type OpendexdRpcParams = _opendexd.RpcParams
 
 type Service struct {
 	*Base
+
+	// Pair is the opendex trading pair, e.g. ETH/BTC
+	Pair string
+	Cex  string
+
+	configErr error
 }
 
 func New(ctx types.Context, name string) (*Service, error) {
@@ -46,6 +53,32 @@ func (t *Service) Apply(cfg interface{}) error {
 		return err
 	}
 
+	if t.Disabled {
+		return nil
+	}
+
+	// a bad arby configuration must not block the unrelated commands, so it
+	// is only reported by Validate
+	apiKey, apiSecret, err := t.loadCredentials(c)
+	if err == nil {
+		err = t.validate(c, apiKey, apiSecret)
+	}
+	t.configErr = nil
+	if err != nil {
+		t.configErr = fmt.Errorf("%s: %w", t.Name, err)
+	}
+	t.Pair = fmt.Sprintf("%s/%s", strings.ToUpper(c.BaseAsset), strings.ToUpper(c.QuoteAsset))
+	t.Cex = strings.ToLower(c.Cex)
+
+	if c.TestMode {
+		if apiKey == "" {
+			apiKey = placeholderApiKey
+		}
+		if apiSecret == "" {
+			apiSecret = placeholderApiSecret
+		}
+	}
+
 	opendexd, err := t.getOpendexd()
 	if err != nil {
 		return err
@@ -72,9 +105,9 @@ func (t *Service) Apply(cfg interface{}) error {
 	t.Environment["QUOTEASSET"] = c.QuoteAsset
 	t.Environment["CEX_BASEASSET"] = c.CexBaseAsset
 	t.Environment["CEX_QUOTEASSET"] = c.CexQuoteAsset
-	t.Environment["CEX"] = fmt.Sprintf("%s", c.Cex)
-	t.Environment["CEX_API_SECRET"] = c.CexApiSecret
-	t.Environment["CEX_API_KEY"] = c.CexApiKey
+	t.Environment["CEX"] = c.Cex
+	t.SetRuntimeSecret("CEX_API_SECRET", apiSecret)
+	t.SetSecret("CEX_API_KEY", apiKey)
 	t.Environment["TEST_MODE"] = fmt.Sprintf("%t", c.TestMode)
 	t.Environment["MARGIN"] = c.Margin
 	t.Environment["TEST_CENTRALIZED_EXCHANGE_BASEASSET_BALANCE"] = c.TestCentralizedBaseassetBalance
@@ -83,6 +116,41 @@ func (t *Service) Apply(cfg interface{}) error {
 	return nil
 }
 
+// Validate returns the configuration error found by Apply
+func (t *Service) Validate() error {
+	return t.configErr
+}
+
+func (t *Service) loadCredentials(c *Config) (string, string, error) {
+	apiKey, err := t.loadCredential("cex-api-key", c.CexApiKey)
+	if err != nil {
+		return "", "", err
+	}
+	apiSecret, err := t.loadCredential("cex-api-secret", c.CexApiSecret)
+	if err != nil {
+		return "", "", err
+	}
+	return apiKey, apiSecret, nil
+}
+
+// loadCredential stores a given CEX credential encrypted, otherwise it reads
+// the stored one
+func (t *Service) loadCredential(key string, value string) (string, error) {
+	store := t.Context.GetSecretStore()
+	name := fmt.Sprintf("%s.%s", t.Name, key)
+	if value != "" {
+		if err := store.Put(name, value); err != nil {
+			return "", fmt.Errorf("store %s: %w", key, err)
+		}
+		return value, nil
+	}
+	value, err := store.Get(name)
+	if err != nil {
+		return "", fmt.Errorf("load %s: %w", key, err)
+	}
+	return value, nil
+}
+
 func (t *Service) GetStatus(ctx context.Context) (string, error) {
 	status, err := t.Base.GetStatus(ctx)
 	if err != nil {
@@ -92,5 +160,28 @@ func (t *Service) GetStatus(ctx context.Context) (string, error) {
 		return status, nil
 	}
 
-	return "Ready", nil
+	// the trade history is only read by arby report, a status poll looks at
+	// the connections, the latest logs and the open orders
+	opendexdState, cexState, err := t.getConnections(ctx, statusLogLines)
+	if err != nil {
+		t.Logger.Debugf("Failed to get connections: %s", err)
+		return "Waiting for opendexd", nil
+	}
+	if opendexdState == Disconnected {
+		return "Disconnected from opendexd", nil
+	}
+	if cexState == Disconnected {
+		return fmt.Sprintf("Disconnected from CEX (%s)", t.Cex), nil
+	}
+
+	od, err := t.getOpendexd()
+	if err != nil {
+		return "", err
+	}
+	orders, err := od.ListOwnOrders(ctx, t.Pair)
+	if err != nil {
+		t.Logger.Debugf("Failed to list orders: %s", err)
+		return "Waiting for opendexd", nil
+	}
+	return fmt.Sprintf("Ready (%s, %d open orders)", t.Pair, len(orders)), nil
 }
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	dt "github.com/docker/docker/api/types"
 	docker "github.com/docker/docker/client"
@@ -27,12 +28,19 @@ type Service struct {
 	Image       string
 	Command     []string
 	Environment map[string]string
-	Ports       []string
-	Volumes     []string
-	Disabled    bool
-	DataDir     string
-
-	client        *docker.Client
+	// SecretEnvironment holds environment variables which should not be
+	// written into docker-compose.yml
+	SecretEnvironment map[string]string
+	// RuntimeEnvironment holds environment variables which are only written
+	// into docker-compose.yml by name. Their values are passed to docker-compose
+	// when the container is created.
+	RuntimeEnvironment map[string]string
+	Ports              []string
+	Volumes            []string
+	Disabled           bool
+	DataDir            string
+
+	client *docker.Client
 
 	Logger *logrus.Entry
 }
@@ -44,19 +52,21 @@ func New(ctx types.Context, name string) (*Service, error) {
 	}
 
 	return &Service{
-		Name:          name,
-		Context:       ctx,
-		client:        client,
-		Logger:        log.NewLogger(fmt.Sprintf("service.%s", name)),
-
-		Hostname:    name,
-		Image:       "",
-		Command:     []string{},
-		Environment: make(map[string]string),
-		Ports:       []string{},
-		Volumes:     []string{},
-		Disabled:    false,
-		DataDir:     "",
+		Name:    name,
+		Context: ctx,
+		client:  client,
+		Logger:  log.NewLogger(fmt.Sprintf("service.%s", name)),
+
+		Hostname:           name,
+		Image:              "",
+		Command:            []string{},
+		Environment:        make(map[string]string),
+		SecretEnvironment:  make(map[string]string),
+		RuntimeEnvironment: make(map[string]string),
+		Ports:              []string{},
+		Volumes:            []string{},
+		Disabled:           false,
+		DataDir:            "",
 	}, nil
 }
 
@@ -130,14 +140,26 @@ func (t *Service) Restart(ctx context.Context) error {
 
 func (t *Service) Create(ctx context.Context) error {
 	c := exec.Command("docker-compose", "up", "-d", "--no-start", t.Name)
+	c.Env = t.runtimeEnv()
 	return utils.Run(ctx, c)
 }
 
 func (t *Service) Up(ctx context.Context) error {
 	c := exec.Command("docker-compose", "up", "-d", t.Name)
+	c.Env = t.runtimeEnv()
 	return utils.Run(ctx, c)
 }
 
+// runtimeEnv returns the launcher environment with the runtime environment
+// variables of the service which docker-compose substitutes into the container
+func (t *Service) runtimeEnv() []string {
+	env := os.Environ()
+	for k, v := range t.RuntimeEnvironment {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env
+}
+
 func (t *Service) demuxLogsReader(reader io.Reader) io.Reader {
 	r, w := io.Pipe()
 	go func() {
@@ -262,6 +284,7 @@ func (t *Service) Apply(cfg interface{}) error {
 	t.Disabled = c.Disabled
 	t.Environment = map[string]string{}
 	t.Environment["NETWORK"] = string(t.Context.GetNetwork())
+	t.SecretEnvironment = map[string]string{}
 	t.DataDir = c.Dir
 	t.Ports = []string{}
 	t.Volumes = []string{}
@@ -274,6 +297,21 @@ func (t *Service) GetImage() string {
 	return t.Image
 }
 
+// GetImageDigest returns the repository digest of the local image, or its ID
+// when the image was built locally
+func (t *Service) GetImageDigest(ctx context.Context) (string, error) {
+	image, _, err := t.client.ImageInspectWithRaw(ctx, t.Image)
+	if err != nil {
+		return "", fmt.Errorf("[docker] image inspect: %w", err)
+	}
+	for _, d := range image.RepoDigests {
+		if i := strings.Index(d, "@"); i >= 0 {
+			return d[i+1:], nil
+		}
+	}
+	return image.ID, nil
+}
+
 func (t *Service) GetHostname() string {
 	return t.Hostname
 }
@@ -286,6 +324,54 @@ func (t *Service) GetEnvironment() map[string]string {
 	return t.Environment
 }
 
+func (t *Service) GetSecretEnvironment() map[string]string {
+	return t.SecretEnvironment
+}
+
+// SetSecret puts a secret environment variable of the service and makes sure
+// the value never shows up in the logs
+func (t *Service) SetSecret(key string, value string) {
+	t.SecretEnvironment[key] = value
+	log.RegisterSecret(value)
+}
+
+// LoadOrCreateSecret returns the generated secret key of the service, which
+// the secret store keeps encrypted. A random secret of length is created on
+// first use so that it stays the same across "gen" runs.
+func (t *Service) LoadOrCreateSecret(key string, length int) (string, error) {
+	store := t.Context.GetSecretStore()
+	name := fmt.Sprintf("%s.%s", t.Name, key)
+	secret, err := store.Get(name)
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		secret, err = utils.RandomString(length)
+		if err != nil {
+			return "", err
+		}
+		if err := store.Put(name, secret); err != nil {
+			return "", fmt.Errorf("store %s: %w", key, err)
+		}
+	}
+	log.RegisterSecret(secret)
+	return secret, nil
+}
+
+func (t *Service) GetRuntimeEnvironment() map[string]string {
+	return t.RuntimeEnvironment
+}
+
+// SetRuntimeSecret puts a secret environment variable of the service which
+// is injected when the container is created instead of being written to the
+// generated files. Docker still keeps it in the container configuration, so
+// it can be read with docker inspect and survives a restart, but a container
+// recreated by a manual "docker-compose up" starts without it.
+func (t *Service) SetRuntimeSecret(key string, value string) {
+	t.RuntimeEnvironment[key] = value
+	log.RegisterSecret(value)
+}
+
 func (t *Service) GetPorts() []string {
 	return t.Ports
 }
@@ -322,6 +408,52 @@ func (t *Service) GetStartedAt(ctx context.Context) (string, error) {
 	return c.State.StartedAt, nil
 }
 
+func (t *Service) GetRestartCount(ctx context.Context) (int, error) {
+	c, err := t.getContainer(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return c.RestartCount, nil
+}
+
+type Stats struct {
+	// CpuUsage is the ratio of host CPU time used by the container where 1.0 means one CPU core
+	CpuUsage    float64
+	MemoryUsage uint64
+	MemoryLimit uint64
+}
+
+func (t *Service) GetStats(ctx context.Context) (*Stats, error) {
+	resp, err := t.client.ContainerStats(ctx, t.GetContainerName(ctx), false)
+	if err != nil {
+		return nil, fmt.Errorf("[docker] container stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var s dt.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return nil, fmt.Errorf("[docker] decode stats: %w", err)
+	}
+
+	result := Stats{
+		MemoryUsage: s.MemoryStats.Usage,
+		MemoryLimit: s.MemoryStats.Limit,
+	}
+
+	// the same calculation as "docker stats" without multiplying 100
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+	if cpuDelta > 0 && systemDelta > 0 {
+		cpus := float64(s.CPUStats.OnlineCPUs)
+		if cpus == 0 {
+			cpus = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+		}
+		result.CpuUsage = cpuDelta / systemDelta * cpus
+	}
+
+	return &result, nil
+}
+
 func (t *Service) GetDataDir() string {
 	return t.DataDir
 }
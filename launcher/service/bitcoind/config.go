@@ -1,9 +1,11 @@
 package bitcoind
 
 import (
+	"fmt"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
 	"path/filepath"
+	"regexp"
 )
 
 type Mode string
@@ -17,16 +19,24 @@ const (
 
 type BaseConfig = base.Config
 
+// Config has no prune option for the native node, it is the lnd backend and
+// lnd 0.11 cannot use a pruned node
 type Config struct {
 	BaseConfig
 
-	Mode           string `usage:"%(name)s service mode"`
-	Rpchost        string `usage:"External %(name)s RPC hostname"`
-	Rpcport        uint16 `usage:"External %(name)s RPC port"`
-	Rpcuser        string `usage:"External %(name)s RPC username"`
-	Rpcpass        string `usage:"External %(name)s RPC password"`
-	Zmqpubrawblock string `usage:"External %(name)s ZeroMQ raw blocks publication address"`
-	Zmqpubrawtx    string `usage:"External %(name)s ZeroMQ raw transactions publication address"`
+	Mode                string   `usage:"%(name)s service mode"`
+	Rpchost             string   `usage:"External %(name)s RPC hostname"`
+	Rpcport             uint16   `usage:"External %(name)s RPC port"`
+	Rpcuser             string   `usage:"External %(name)s RPC username"`
+	Rpcpass             string   `usage:"External %(name)s RPC password"`
+	Zmqpubrawblock      string   `usage:"External %(name)s ZeroMQ raw blocks publication address"`
+	Zmqpubrawtx         string   `usage:"External %(name)s ZeroMQ raw transactions publication address"`
+	SkipUnresolvedProbe bool     `usage:"Skip probing external %(name)s endpoints whose host cannot be resolved from this machine instead of failing"`
+	Dbcache             uint     `usage:"Native %(name)s database cache size in MiB (0 uses the node default)"`
+	Txindex             bool     `usage:"Native %(name)s maintains a full transaction index"`
+	Assumevalid         string   `usage:"Native %(name)s assumes the ancestors of this block hash have valid scripts"`
+	Maxconnections      uint     `usage:"Native %(name)s maximum peer connections (0 uses the node default)"`
+	Addnode             []string `usage:"Native %(name)s additional peers to connect to"`
 }
 
 func (t *Service) GetDefaultConfig() interface{} {
@@ -40,16 +50,57 @@ func (t *Service) GetDefaultConfig() interface{} {
 
 	return &Config{
 		BaseConfig: BaseConfig{
-			Image:    t.Base.GetBranchImage(image),
-			Disabled: true,
+			Image: t.Base.GetBranchImage(image),
+			// the node container only runs in the native mode, which
+			// Apply enforces
+			Disabled: false,
 			Dir:      filepath.Join(t.Context.GetDataDir(), t.Name),
 		},
-		Mode:           Light,
-		Rpchost:        "",
-		Rpcport:        0,
-		Rpcuser:        "",
-		Rpcpass:        "",
-		Zmqpubrawblock: "",
-		Zmqpubrawtx:    "",
+		Mode:                Light,
+		Rpchost:             "",
+		Rpcport:             0,
+		Rpcuser:             "",
+		Rpcpass:             "",
+		Zmqpubrawblock:      "",
+		Zmqpubrawtx:         "",
+		SkipUnresolvedProbe: false,
+		Dbcache:             0,
+		Txindex:             true,
+		Assumevalid:         "",
+		Maxconnections:      0,
+		Addnode:             []string{},
 	}
 }
+
+// validateNodeOptions checks the native node options against the
+// requirements of the node and lnd
+func (t *Service) validateNodeOptions(c *Config) error {
+	if !c.Txindex {
+		t.Logger.Warnf("txindex is off, lnd falls back to slower block scans to look up transactions")
+	}
+	if c.Assumevalid != "" && c.Assumevalid != "0" && !reBlockHash.MatchString(c.Assumevalid) {
+		return fmt.Errorf("invalid assumevalid block hash: %s", c.Assumevalid)
+	}
+	return nil
+}
+
+var reBlockHash = regexp.MustCompile("^[0-9a-fA-F]{64}$")
+
+// NodeOptions translates the native node options except txindex into
+// command-line options
+func NodeOptions(c *Config) []string {
+	var opts []string
+	if c.Dbcache > 0 {
+		opts = append(opts, fmt.Sprintf("-dbcache=%d", c.Dbcache))
+	}
+	if c.Assumevalid != "" {
+		opts = append(opts, fmt.Sprintf("-assumevalid=%s", c.Assumevalid))
+	}
+	if c.Maxconnections > 0 {
+		opts = append(opts, fmt.Sprintf("-maxconnections=%d", c.Maxconnections))
+	}
+	for _, node := range c.Addnode {
+		opts = append(opts, fmt.Sprintf("-addnode=%s", node))
+	}
+	return opts
+}
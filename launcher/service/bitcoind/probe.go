@@ -0,0 +1,116 @@
+package bitcoind
+
+import (
+	"context"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"github.com/opendexnetwork/opendex-docker/launcher/utils"
+	"net"
+	"net/url"
+	"time"
+)
+
+// BitcoinGenesis are the genesis block hashes of the Bitcoin networks
+var BitcoinGenesis = map[string]string{
+	"main": "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+	"test": "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943",
+}
+
+const zmqDialTimeout = 5 * time.Second
+
+// Probe checks the external node before the services depending on it are
+// started: the RPC credentials, the chain, the sync state and the ZeroMQ
+// endpoints. It does nothing in other modes.
+func (t *Service) Probe(ctx context.Context) error {
+	if t.Mode != External {
+		return nil
+	}
+
+	p := t.RpcParams
+	addr := fmt.Sprintf("%s:%d", p.Host, p.Port)
+	if p.Host == "" || p.Port == 0 {
+		return fmt.Errorf("external %s RPC host and port are required", t.Name)
+	}
+
+	if !utils.Resolvable(ctx, p.Host) {
+		if !t.SkipUnresolvedProbe {
+			return fmt.Errorf("external %s RPC host %s cannot be resolved, use --%s.skip-unresolved-probe if only the containers can resolve it", t.Name, p.Host, t.Name)
+		}
+		// lnd may still reach it through the docker network
+		t.Logger.Warnf("Skipping the probe of external %s, %s cannot be resolved from the host", t.Name, p.Host)
+		return nil
+	}
+
+	client := NewRpcClient(p)
+
+	var info BlockchainInfo
+	if err := client.Call(ctx, "getblockchaininfo", &info); err != nil {
+		return fmt.Errorf("external %s RPC at %s: %w", t.Name, addr, err)
+	}
+
+	chain := t.expectedChain()
+	if info.Chain != chain {
+		return fmt.Errorf("external %s at %s is on chain %q but %s requires %q", t.Name, addr, info.Chain, t.Context.GetNetwork(), chain)
+	}
+
+	var genesis string
+	if err := client.Call(ctx, "getblockhash", &genesis, 0); err != nil {
+		return fmt.Errorf("external %s RPC at %s: get genesis block: %w", t.Name, addr, err)
+	}
+	if expected := t.Genesis[chain]; expected != "" && genesis != expected {
+		return fmt.Errorf("external %s at %s has unexpected genesis block %s (expected %s)", t.Name, addr, genesis, expected)
+	}
+
+	if info.InitialBlockDownload {
+		return fmt.Errorf("external %s at %s is still syncing (%.2f%%, %d/%d)", t.Name, addr, info.VerificationProgress*100, info.Blocks, info.Headers)
+	}
+
+	for _, endpoint := range []struct {
+		option string
+		value  string
+	}{
+		{"zmqpubrawblock", p.Zmqpubrawblock},
+		{"zmqpubrawtx", p.Zmqpubrawtx},
+	} {
+		if err := t.probeZmq(ctx, endpoint.value); err != nil {
+			return fmt.Errorf("external %s %s: %w", t.Name, endpoint.option, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *Service) expectedChain() string {
+	switch t.Context.GetNetwork() {
+	case types.Mainnet:
+		return "main"
+	case types.Testnet:
+		return "test"
+	default:
+		return "regtest"
+	}
+}
+
+// probeZmq makes sure something is listening on the ZeroMQ endpoint
+func (t *Service) probeZmq(ctx context.Context, endpoint string) error {
+	if endpoint == "" {
+		return fmt.Errorf("address is required")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "tcp" || u.Port() == "" {
+		return fmt.Errorf("invalid address %q (expected tcp://host:port)", endpoint)
+	}
+	if !utils.Resolvable(ctx, u.Hostname()) {
+		if !t.SkipUnresolvedProbe {
+			return fmt.Errorf("host %s cannot be resolved, use --%s.skip-unresolved-probe if only the containers can resolve it", u.Hostname(), t.Name)
+		}
+		t.Logger.Warnf("Skipping the probe of %s, %s cannot be resolved from the host", endpoint, u.Hostname())
+		return nil
+	}
+	d := net.Dialer{Timeout: zmqDialTimeout}
+	conn, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return fmt.Errorf("cannot connect to %s: %w", u.Host, err)
+	}
+	return conn.Close()
+}
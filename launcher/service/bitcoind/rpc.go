@@ -0,0 +1,90 @@
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RpcClient is a minimal JSON-RPC client of bitcoind and litecoind
+type RpcClient struct {
+	Url      string
+	Username string
+	Password string
+
+	client *http.Client
+}
+
+func NewRpcClient(params RpcParams) *RpcClient {
+	return &RpcClient{
+		Url:      fmt.Sprintf("http://%s:%d", params.Host, params.Port),
+		Username: params.Username,
+		Password: params.Password,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type rpcRequest struct {
+	JsonRpc string        `json:"jsonrpc"`
+	Id      string        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type RpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (t *RpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", t.Code, t.Message)
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RpcError       `json:"error"`
+}
+
+func (t *RpcClient) Call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcRequest{JsonRpc: "1.0", Id: "launcher", Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", t.Url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(t.Username, t.Password)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("[http %d] invalid RPC credentials", resp.StatusCode)
+	}
+
+	var r rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("[http %d] decode response: %w", resp.StatusCode, err)
+	}
+	if r.Error != nil {
+		return r.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(r.Result, result); err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
+	return nil
+}
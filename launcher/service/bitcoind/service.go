@@ -1,10 +1,21 @@
 package bitcoind
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker/launcher/service"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"strings"
+)
+
+const (
+	// NativeRpcUser is the RPC username of native bitcoind/litecoind containers
+	NativeRpcUser = "opendex"
 )
 
 type Base = base.Service
@@ -12,8 +23,17 @@ type Base = base.Service
 type Service struct {
 	*Base
 	ContainerDataDir string
-	RpcParams RpcParams
-	Mode Mode
+	RpcParams        RpcParams
+	Mode             Mode
+	// ZmqPorts are the ZeroMQ raw block and raw transaction publication ports in native mode
+	ZmqPorts [2]uint16
+	// Cli is the RPC client command of the node in the container
+	Cli string
+	// Genesis maps chain names to genesis block hashes to probe external nodes
+	Genesis map[string]string
+	// SkipUnresolvedProbe skips probing external endpoints which cannot be
+	// resolved from the host instead of failing
+	SkipUnresolvedProbe bool
 }
 
 type RpcParams struct {
@@ -39,10 +59,34 @@ func New(ctx types.Context, name string) (*Service, error) {
 	return &Service{
 		Base:             s,
 		ContainerDataDir: "/root/.bitcoind",
-		RpcParams: RpcParams{},
+		RpcParams:        RpcParams{},
+		ZmqPorts:         [2]uint16{28332, 28333},
+		Cli:              "bitcoin-cli",
+		Genesis:          BitcoinGenesis,
 	}, nil
 }
 
+// GetNativeRpcPassword returns the RPC password of the native node which is
+// generated once per install and kept in the secret store
+func (t *Service) GetNativeRpcPassword() (string, error) {
+	return t.LoadOrCreateSecret("rpcpass", 32)
+}
+
+// getNativeRpcSalt returns the random rpcauth salt which is kept next to the
+// RPC password, so that the generated command is stable across "gen" runs
+func (t *Service) getNativeRpcSalt() (string, error) {
+	return t.LoadOrCreateSecret("rpcsalt", 32)
+}
+
+// RpcAuth generates the value of bitcoind's -rpcauth option like
+// share/rpcauth/rpcauth.py so that the plaintext password isn't needed by
+// bitcoind
+func RpcAuth(user string, password string, salt string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(password))
+	return fmt.Sprintf("%s:%s$%s", user, salt, hex.EncodeToString(mac.Sum(nil)))
+}
+
 func (t *Service) Apply(cfg interface{}) error {
 	c := cfg.(*Config)
 	if err := t.Base.Apply(c.BaseConfig); err != nil {
@@ -50,23 +94,58 @@ func (t *Service) Apply(cfg interface{}) error {
 	}
 	t.Volumes = append(t.Volumes, fmt.Sprintf("%s:%s", t.DataDir, t.ContainerDataDir))
 
-	t.RpcParams.Type = "JSON-RPC"
+	t.RpcParams = RpcParams{Type: "JSON-RPC"}
 	t.Mode = Mode(c.Mode)
+	t.SkipUnresolvedProbe = c.SkipUnresolvedProbe
+
+	// only the native mode runs a node container
+	if t.Mode != Native {
+		t.Disabled = true
+	}
 
 	network := t.Context.GetNetwork()
 
 	switch t.Mode {
 	case Native:
+		password, err := t.GetNativeRpcPassword()
+		if err != nil {
+			return fmt.Errorf("get rpc password: %w", err)
+		}
 		t.RpcParams.Host = t.Name
 		if network == types.Mainnet {
 			t.RpcParams.Port = 8332
 		} else {
 			t.RpcParams.Port = 18332
 		}
-		t.RpcParams.Username = "on"
-		t.RpcParams.Password = "on"
-		t.RpcParams.Zmqpubrawblock = fmt.Sprintf("tcp://%s:28332", t.Name)
-		t.RpcParams.Zmqpubrawtx = fmt.Sprintf("tcp://%s:28333", t.Name)
+		t.RpcParams.Username = NativeRpcUser
+		t.RpcParams.Password = password
+		t.RpcParams.Zmqpubrawblock = fmt.Sprintf("tcp://%s:%d", t.Name, t.ZmqPorts[0])
+		t.RpcParams.Zmqpubrawtx = fmt.Sprintf("tcp://%s:%d", t.Name, t.ZmqPorts[1])
+
+		if err := t.validateNodeOptions(c); err != nil {
+			return fmt.Errorf("%s: %w", t.Name, err)
+		}
+
+		salt, err := t.getNativeRpcSalt()
+		if err != nil {
+			return fmt.Errorf("get rpc salt: %w", err)
+		}
+		rpcauth := RpcAuth(NativeRpcUser, password, salt)
+		t.Command = append(t.Command,
+			"-server",
+			fmt.Sprintf("-datadir=%s", t.ContainerDataDir),
+			"-disablewallet",
+			fmt.Sprintf("-txindex=%d", boolToInt(c.Txindex)),
+			fmt.Sprintf("-rpcauth=%s", rpcauth),
+			"-rpcbind=0.0.0.0",
+			"-rpcallowip=0.0.0.0/0",
+			fmt.Sprintf("-zmqpubrawblock=tcp://0.0.0.0:%d", t.ZmqPorts[0]),
+			fmt.Sprintf("-zmqpubrawtx=tcp://0.0.0.0:%d", t.ZmqPorts[1]),
+		)
+		if network == types.Testnet {
+			t.Command = append(t.Command, "-testnet")
+		}
+		t.Command = append(t.Command, NodeOptions(c)...)
 	case External:
 		t.RpcParams.Host = c.Rpchost
 		t.RpcParams.Port = c.Rpcport
@@ -88,3 +167,130 @@ func (t *Service) GetRpcParams() (interface{}, error) {
 func (t *Service) GetMode() string {
 	return string(t.Mode)
 }
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// call invokes an RPC method of the node. Native nodes are called with the
+// RPC client in the container and external nodes are called over JSON-RPC.
+func (t *Service) call(ctx context.Context, method string, result interface{}) error {
+	switch t.Mode {
+	case Native:
+		output, err := t.Exec(ctx, t.Cli,
+			"-rpcconnect=127.0.0.1",
+			fmt.Sprintf("-rpcport=%d", t.RpcParams.Port),
+			fmt.Sprintf("-rpcuser=%s", t.RpcParams.Username),
+			fmt.Sprintf("-rpcpassword=%s", t.RpcParams.Password),
+			method,
+		)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(output), result); err != nil {
+			return fmt.Errorf("failed to parse output as JSON: %#v", output)
+		}
+		return nil
+	case External:
+		return NewRpcClient(t.RpcParams).Call(ctx, method, result)
+	default:
+		return fmt.Errorf("no RPC in %s mode", t.Mode)
+	}
+}
+
+type BlockchainInfo struct {
+	Chain                string  `json:"chain"`
+	Blocks               uint64  `json:"blocks"`
+	Headers              uint64  `json:"headers"`
+	VerificationProgress float64 `json:"verificationprogress"`
+	InitialBlockDownload bool    `json:"initialblockdownload"`
+	Pruned               bool    `json:"pruned"`
+	PruneHeight          uint64  `json:"pruneheight"`
+	SizeOnDisk           uint64  `json:"size_on_disk"`
+}
+
+func (t *Service) GetBlockchainInfo(ctx context.Context) (*BlockchainInfo, error) {
+	var info BlockchainInfo
+	if err := t.call(ctx, "getblockchaininfo", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (t *Service) GetConnectionCount(ctx context.Context) (uint, error) {
+	var count uint
+	if err := t.call(ctx, "getconnectioncount", &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+type Info struct {
+	BlockchainInfo
+	Peers uint `json:"peers"`
+}
+
+func (t *Service) GetInfo(ctx context.Context) (*Info, error) {
+	bc, err := t.GetBlockchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	peers, err := t.GetConnectionCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{BlockchainInfo: *bc, Peers: peers}, nil
+}
+
+func (t *Service) getStatusFromInfo(info *Info) string {
+	if info.InitialBlockDownload || info.Blocks < info.Headers {
+		p := info.VerificationProgress * 100
+		if p > 99.99 && info.Blocks < info.Headers {
+			p = 99.99
+		}
+		status := fmt.Sprintf("Syncing %.2f%% (%d/%d), %d peers", p, info.Blocks, info.Headers, info.Peers)
+		if info.Pruned {
+			status += fmt.Sprintf(", pruned %d MiB", info.SizeOnDisk/1024/1024)
+		}
+		return status
+	}
+	if info.Peers == 0 {
+		return "Waiting for peers"
+	}
+	return "Ready"
+}
+
+func (t *Service) GetStatus(ctx context.Context) (string, error) {
+	switch t.Mode {
+	case Native:
+		status, err := t.Base.GetStatus(ctx)
+		if err != nil {
+			return "", err
+		}
+		if status != "Container running" {
+			return status, nil
+		}
+	case External:
+	default:
+		return fmt.Sprintf("Disabled (%s mode)", t.Mode), nil
+	}
+
+	info, err := t.GetInfo(ctx)
+	if err != nil {
+		if err, ok := err.(service.ErrExec); ok {
+			// RPC is not ready while the node is loading blocks, e.g. "error code: -28"
+			if strings.Contains(err.Output, "error code: -28") || strings.Contains(err.Output, "Could not connect to the server") {
+				return "Starting...", nil
+			}
+		}
+		if err, ok := err.(*RpcError); ok && err.Code == -28 {
+			return "Starting...", nil
+		}
+		return "", err
+	}
+
+	return t.getStatusFromInfo(info), nil
+}
@@ -29,32 +29,45 @@ func New(ctx types.Context, name string) (*Service, error) {
 	}, nil
 }
 
+// Info is nil for a chain whose boltz daemon is down
 type Info struct {
-	Bitcoin  string
-	Litecoin string
+	Bitcoin  *ChainInfo
+	Litecoin *ChainInfo
+	Swaps    SwapsSummary
 }
 
 func (t *Service) GetInfo(ctx context.Context) (*Info, error) {
-	var err error
 	info := Info{}
 
-	_, err = t.Exec(ctx, "wrapper", "btc", "getinfo")
-	if err != nil {
-		info.Bitcoin = "down"
-	} else {
-		info.Bitcoin = "up"
-	}
-
-	_, err = t.Exec(ctx, "wrapper", "ltc", "getinfo")
-	if err != nil {
-		info.Litecoin = "down"
-	} else {
-		info.Litecoin = "up"
+	for _, chain := range Chains {
+		chainInfo, err := t.GetChainInfo(ctx, chain)
+		if err != nil {
+			t.Logger.Debugf("Failed to get %s info: %s", chain, err)
+			continue
+		}
+		if chain == "btc" {
+			info.Bitcoin = chainInfo
+		} else {
+			info.Litecoin = chainInfo
+		}
+
+		swaps, err := t.ListSwaps(ctx, chain)
+		if err != nil {
+			return nil, fmt.Errorf("list %s swaps: %w", chain, err)
+		}
+		info.Swaps.add(swaps)
 	}
 
 	return &info, nil
 }
 
+func upOrDown(info *ChainInfo) string {
+	if info == nil {
+		return "down"
+	}
+	return "up"
+}
+
 func (t *Service) GetStatus(ctx context.Context) (string, error) {
 	status, err := t.Base.GetStatus(ctx)
 	if err != nil {
@@ -69,11 +82,14 @@ func (t *Service) GetStatus(ctx context.Context) (string, error) {
 		return "", err
 	}
 
-	if info.Bitcoin == "up" && info.Litecoin == "up" {
-		return "Ready", nil
+	if info.Bitcoin == nil || info.Litecoin == nil {
+		return fmt.Sprintf("btc %s; ltc %s", upOrDown(info.Bitcoin), upOrDown(info.Litecoin)), nil
 	}
 
-	return fmt.Sprintf("btc %s; ltc %s", info.Bitcoin, info.Litecoin), nil
+	if summary := info.Swaps.String(); summary != "" {
+		return fmt.Sprintf("Ready (%s)", summary), nil
+	}
+	return "Ready", nil
 }
 
 func (t *Service) Apply(cfg interface{}) error {
@@ -0,0 +1,313 @@
+package boltz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Chains are the chains boltz swaps on, as the wrapper names them
+var Chains = []string{"btc", "ltc"}
+
+// swap states of boltz-lnd
+const (
+	StatePending     = "PENDING"
+	StateSuccessful  = "SUCCESSFUL"
+	StateError       = "ERROR"
+	StateServerError = "SERVER_ERROR"
+	StateRefunded    = "REFUNDED"
+	StateAbandoned   = "ABANDONED"
+)
+
+func checkChain(chain string) error {
+	for _, c := range Chains {
+		if c == chain {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported chain %q (supported: %s)", chain, strings.Join(Chains, ", "))
+}
+
+// cli runs a boltzcli command of chain and decodes its JSON output into
+// result
+func (t *Service) cli(ctx context.Context, chain string, result interface{}, args ...string) error {
+	if err := checkChain(chain); err != nil {
+		return err
+	}
+	output, err := t.Exec(ctx, "wrapper", append([]string{chain}, args...)...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(output), result); err != nil {
+		return errors.New(strings.TrimSpace(output))
+	}
+	return nil
+}
+
+type ChainInfo struct {
+	Symbol              string   `json:"symbol"`
+	Network             string   `json:"network"`
+	LndPubkey           string   `json:"lndPubkey"`
+	BlockHeight         uint32   `json:"blockHeight"`
+	PendingSwaps        []string `json:"pendingSwaps"`
+	PendingReverseSwaps []string `json:"pendingReverseSwaps"`
+}
+
+func (t *Service) GetChainInfo(ctx context.Context, chain string) (*ChainInfo, error) {
+	var info ChainInfo
+	if err := t.cli(ctx, chain, &info, "getinfo"); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Swap is a submarine swap (swap in) paying a lightning invoice for onchain
+// coins. Amounts are in satoshis.
+type Swap struct {
+	Id                  string `json:"id"`
+	State               string `json:"state"`
+	Error               string `json:"error"`
+	Status              string `json:"status"`
+	Invoice             string `json:"invoice"`
+	LockupAddress       string `json:"lockupAddress"`
+	ExpectedAmount      int64  `json:"expectedAmount,string"`
+	TimeoutBlockHeight  uint32 `json:"timeoutBlockHeight"`
+	LockupTransactionId string `json:"lockupTransactionId"`
+	RefundTransactionId string `json:"refundTransactionId"`
+}
+
+// NeedsRefund tells whether the onchain coins of a failed swap are still
+// locked up. boltz refunds them once the timeout block height is reached.
+func (t *Swap) NeedsRefund() bool {
+	switch t.State {
+	case StateError, StateServerError, StateAbandoned:
+		return t.LockupTransactionId != "" && t.RefundTransactionId == ""
+	}
+	return false
+}
+
+// ReverseSwap pays onchain coins for a lightning payment (swap out). Amounts
+// are in satoshis.
+type ReverseSwap struct {
+	Id                  string `json:"id"`
+	State               string `json:"state"`
+	Error               string `json:"error"`
+	Status              string `json:"status"`
+	Invoice             string `json:"invoice"`
+	ClaimAddress        string `json:"claimAddress"`
+	OnchainAmount       int64  `json:"onchainAmount,string"`
+	TimeoutBlockHeight  uint32 `json:"timeoutBlockHeight"`
+	LockupTransactionId string `json:"lockupTransactionId"`
+	ClaimTransactionId  string `json:"claimTransactionId"`
+}
+
+// ChannelCreation is a swap which opens a channel from boltz to the node
+type ChannelCreation struct {
+	Swap            Swap `json:"swap"`
+	ChannelCreation struct {
+		Status               string `json:"status"`
+		InboundLiquidity     uint32 `json:"inboundLiquidity"`
+		Private              bool   `json:"private"`
+		FundingTransactionId string `json:"fundingTransactionId"`
+	} `json:"channelCreation"`
+}
+
+type Swaps struct {
+	Swaps            []Swap            `json:"swaps"`
+	ChannelCreations []ChannelCreation `json:"channelCreations"`
+	ReverseSwaps     []ReverseSwap     `json:"reverseSwaps"`
+}
+
+func (t *Service) ListSwaps(ctx context.Context, chain string) (*Swaps, error) {
+	var swaps Swaps
+	if err := t.cli(ctx, chain, &swaps, "listswaps"); err != nil {
+		return nil, err
+	}
+	return &swaps, nil
+}
+
+// SwapInfo is a swap or reverse swap looked up by id
+type SwapInfo struct {
+	Swap            *Swap        `json:"swap"`
+	ChannelCreation interface{}  `json:"channelCreation"`
+	ReverseSwap     *ReverseSwap `json:"reverseSwap"`
+}
+
+func (t *Service) GetSwapInfo(ctx context.Context, chain string, id string) (*SwapInfo, error) {
+	var info SwapInfo
+	if err := t.cli(ctx, chain, &info, "swapinfo", id); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ServiceInfo are the fees and limits of the boltz service of a chain
+type ServiceInfo struct {
+	Fees struct {
+		// Percentage is the service fee in percent of the amount
+		Percentage float64 `json:"percentage"`
+		// Miner are the onchain fees in satoshis
+		Miner struct {
+			Normal  uint64 `json:"normal"`
+			Reverse uint64 `json:"reverse"`
+		} `json:"miner"`
+	} `json:"fees"`
+	Limits struct {
+		Minimal int64 `json:"minimal,string"`
+		Maximal int64 `json:"maximal,string"`
+	} `json:"limits"`
+}
+
+func (t *Service) GetServiceInfo(ctx context.Context, chain string) (*ServiceInfo, error) {
+	var info ServiceInfo
+	if err := t.cli(ctx, chain, &info, "getserviceinfo"); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Fee returns the fee in satoshis of a swap (or a reverse swap) of amount
+// satoshis the way boltzcli computes it for its confirmation
+func (t *ServiceInfo) Fee(amount uint64, reverse bool) uint64 {
+	fee := uint64(math.Ceil(float64(amount) * t.Fees.Percentage / 100))
+	if reverse {
+		return fee + t.Fees.Miner.Reverse
+	}
+	return fee + t.Fees.Miner.Normal
+}
+
+// GetFee quotes the fee in satoshis of a swap (or a reverse swap) of amount
+// satoshis
+func (t *Service) GetFee(ctx context.Context, chain string, amount uint64, reverse bool) (uint64, error) {
+	info, err := t.GetServiceInfo(ctx, chain)
+	if err != nil {
+		return 0, fmt.Errorf("get service info: %w", err)
+	}
+	return info.Fee(amount, reverse), nil
+}
+
+// checkFee fails when the fee boltzcli is going to confirm exceeds maxFee
+func (t *Service) checkFee(ctx context.Context, chain string, amount uint64, reverse bool, maxFee uint64) error {
+	fee, err := t.GetFee(ctx, chain, amount, reverse)
+	if err != nil {
+		return err
+	}
+	if fee > maxFee {
+		return fmt.Errorf("the fee rose to %d satoshis, above the accepted %d satoshis", fee, maxFee)
+	}
+	return nil
+}
+
+var reSwapId = regexp.MustCompile(`"id":\s*"([^"]+)"`)
+
+// createSwap runs a boltzcli create command. boltzcli asks on stdin to
+// confirm the fees, which an exec doesn't attach, so the answer is piped in
+// (the launcher asks before and checks the fee with checkFee). It returns the id of the created swap and the
+// response of boltzcli.
+func (t *Service) createSwap(ctx context.Context, args ...string) (string, string, error) {
+	output, err := t.Exec(ctx, "bash", append([]string{"-c", `yes | wrapper "$@"`, "wrapper"}, args...)...)
+	if err != nil {
+		return "", "", err
+	}
+	output = strings.TrimSpace(output)
+	m := reSwapId.FindStringSubmatch(output)
+	if m == nil {
+		// e.g. the confirmation was not accepted
+		return "", output, fmt.Errorf("no swap was created: %s", output)
+	}
+	return m[1], output, nil
+}
+
+// CreateSwap swaps amount satoshis of onchain coins from the lnd wallet into
+// the lightning channels unless the fee exceeds maxFee satoshis
+func (t *Service) CreateSwap(ctx context.Context, chain string, amount uint64, maxFee uint64) (string, string, error) {
+	if err := checkChain(chain); err != nil {
+		return "", "", err
+	}
+	if err := t.checkFee(ctx, chain, amount, false, maxFee); err != nil {
+		return "", "", err
+	}
+	return t.createSwap(ctx, chain, "createswap", fmt.Sprintf("%d", amount))
+}
+
+// CreateReverseSwap swaps amount satoshis out of the lightning channels to
+// address, the lnd wallet if address is empty, unless the fee exceeds maxFee
+// satoshis
+func (t *Service) CreateReverseSwap(ctx context.Context, chain string, amount uint64, address string, maxFee uint64) (string, string, error) {
+	if err := checkChain(chain); err != nil {
+		return "", "", err
+	}
+	if err := t.checkFee(ctx, chain, amount, true, maxFee); err != nil {
+		return "", "", err
+	}
+	args := []string{chain, "createreverseswap", fmt.Sprintf("%d", amount)}
+	if address != "" {
+		args = append(args, address)
+	}
+	return t.createSwap(ctx, args...)
+}
+
+// SwapsSummary counts the swaps which need attention
+type SwapsSummary struct {
+	PendingSwaps            int `json:"pendingSwaps"`
+	PendingReverseSwaps     int `json:"pendingReverseSwaps"`
+	PendingChannelCreations int `json:"pendingChannelCreations"`
+	AwaitingRefund          int `json:"awaitingRefund"`
+	Refunded                int `json:"refunded"`
+}
+
+func (t *SwapsSummary) add(swaps *Swaps) {
+	for _, s := range swaps.Swaps {
+		if s.State == StatePending {
+			t.PendingSwaps++
+		}
+		if s.NeedsRefund() {
+			t.AwaitingRefund++
+		}
+		if s.State == StateRefunded {
+			t.Refunded++
+		}
+	}
+	for _, s := range swaps.ReverseSwaps {
+		if s.State == StatePending {
+			t.PendingReverseSwaps++
+		}
+	}
+	for _, c := range swaps.ChannelCreations {
+		if c.Swap.State == StatePending {
+			t.PendingChannelCreations++
+		}
+		if c.Swap.NeedsRefund() {
+			t.AwaitingRefund++
+		}
+	}
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// String lists the non-zero counts, e.g. "1 pending swap, 1 awaiting refund"
+func (t SwapsSummary) String() string {
+	var parts []string
+	if t.PendingSwaps > 0 {
+		parts = append(parts, plural(t.PendingSwaps, "pending swap"))
+	}
+	if t.PendingReverseSwaps > 0 {
+		parts = append(parts, plural(t.PendingReverseSwaps, "pending reverse swap"))
+	}
+	if t.PendingChannelCreations > 0 {
+		parts = append(parts, plural(t.PendingChannelCreations, "pending channel creation"))
+	}
+	if t.AwaitingRefund > 0 {
+		parts = append(parts, fmt.Sprintf("%d awaiting refund", t.AwaitingRefund))
+	}
+	return strings.Join(parts, ", ")
+}
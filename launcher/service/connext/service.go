@@ -4,23 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/log"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/geth"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
-	"math/rand"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Base = base.Service
 
+const (
+	MessagingUrl = "https://messaging.connext.network"
+)
+
 var (
-	passwordRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
 	// This is a placeholder mnemonic that is required for startup, but is not being used since it will be overwritten by opendexd.
 	placeholderMnemonic = "crazy angry east hood fiber awake leg knife entire excite output scheme"
 )
 
 type Service struct {
 	*Base
+
+	// AdminToken authenticates the admin API of the vector node
+	AdminToken string
+
+	detailsMu sync.Mutex
+	// details caches the latest status details for StatusDetailsTtl
+	details        string
+	detailsChecked time.Time
 }
 
 func New(ctx types.Context, name string) (*Service, error) {
@@ -58,11 +71,20 @@ func (t *Service) GetStatus(ctx context.Context) (string, error) {
 		return status, nil
 	}
 
-	if t.IsHealthy(ctx) {
+	if !t.IsHealthy(ctx) {
+		return "Starting...", nil
+	}
+
+	if !t.UseVector() {
 		return "Ready", nil
 	}
 
-	return "Starting...", nil
+	details, err := t.getStatusDetails(ctx)
+	if err != nil {
+		t.Logger.Debugf("Failed to get status details: %s", err)
+		return "Ready", nil
+	}
+	return fmt.Sprintf("Ready (%s)", details), nil
 }
 
 func (t *Service) UseVector() bool {
@@ -104,11 +126,21 @@ func (t *Service) Apply(cfg interface{}) error {
 			chainId = "1"
 		}
 
-		t.Environment["VECTOR_CONFIG"] = t.getVectorConfig(chainId, channelFactoryAddress, transferRegistryAddress, ethProvider)
+		token, err := t.getAdminToken()
+		if err != nil {
+			return fmt.Errorf("get admin token: %w", err)
+		}
+		t.AdminToken = token
+		log.RegisterSecret(token)
+
+		// VECTOR_CONFIG contains the admin token
+		t.SetSecret("VECTOR_CONFIG", t.getVectorConfig(chainId, channelFactoryAddress, transferRegistryAddress, ethProvider))
 		t.Environment["VECTOR_SQLITE_FILE"] = "/database/store.db"
 		t.Environment["VECTOR_PROD"] = "true"
 	} else {
 		// legacy connext indra stuff
+		// indra only supports a single provider
+		ethProvider = strings.Split(ethProvider, ",")[0]
 
 		t.Environment["LEGACY_MODE"] = "true"
 		switch network {
@@ -133,12 +165,7 @@ func (t *Service) getEthProvider() (string, error) {
 		return "", err
 	}
 
-	params, err := s.GetRpcParams()
-	if err != nil {
-		return "", err
-	}
-
-	return params.(geth.RpcParams).ToUri(), nil
+	return s.(*geth.Service).GetProvidersUri(), nil
 }
 
 func (t *Service) GetRpcParams() (interface{}, error) {
@@ -149,18 +176,15 @@ func (t *Service) GetRpcParams() (interface{}, error) {
 	return params, nil
 }
 
-func (t *Service) generateAdminToken(length int) string {
-	b := make([]rune, length)
-	n := len(passwordRunes)
-	for i := range b {
-		b[i] = passwordRunes[rand.Intn(n)]
-	}
-	return string(b)
+// getAdminToken returns the admin token kept in the secret store so that it
+// stays the same across "gen" runs
+func (t *Service) getAdminToken() (string, error) {
+	return t.LoadOrCreateSecret("admintoken", 32)
 }
 
 func (t *Service) getVectorConfig(chainId, channelFactoryAddress, transferRegistryAddress, ethProvider string) string {
 	config := map[string]interface{}{
-		"adminToken": t.generateAdminToken(20),
+		"adminToken": t.AdminToken,
 		"chainAddresses": map[string]interface{}{
 			chainId: map[string]interface{}{
 				"channelFactoryAddress":   channelFactoryAddress,
@@ -172,7 +196,7 @@ func (t *Service) getVectorConfig(chainId, channelFactoryAddress, transferRegist
 		},
 		"domainName":   "",
 		"logLevel":     "debug",
-		"messagingUrl": "https://messaging.connext.network",
+		"messagingUrl": MessagingUrl,
 		"production":   true,
 		"mnemonic":     placeholderMnemonic,
 	}
@@ -181,7 +205,8 @@ func (t *Service) getVectorConfig(chainId, channelFactoryAddress, transferRegist
 		// addresses need to be specified manually
 		delete(config, "chainAddresses")
 	}
-	j, err := json.MarshalIndent(config, "", "    ")
+	// env files don't support multiline values
+	j, err := json.Marshal(config)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,173 @@
+package connext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// EthAssetId is the asset ID of ETH in vector channels
+const EthAssetId = "0x0000000000000000000000000000000000000000"
+
+// StatusDetailsTtl is how long status polls reuse the channel summary, which
+// takes an API call for every channel
+const StatusDetailsTtl = time.Minute
+
+type NodeConfig struct {
+	Index            int    `json:"index"`
+	PublicIdentifier string `json:"publicIdentifier"`
+	SignerAddress    string `json:"signerAddress"`
+}
+
+type Balance struct {
+	Amount []string `json:"amount"`
+	To     []string `json:"to"`
+}
+
+type Channel struct {
+	ChannelAddress  string    `json:"channelAddress"`
+	AliceIdentifier string    `json:"aliceIdentifier"`
+	BobIdentifier   string    `json:"bobIdentifier"`
+	AssetIds        []string  `json:"assetIds"`
+	Balances        []Balance `json:"balances"`
+}
+
+// apiGet calls the node API in the container with the admin token
+func (t *Service) apiGet(ctx context.Context, path string, result interface{}) error {
+	output, err := t.Exec(ctx, "curl", "-s", "-f",
+		"-H", fmt.Sprintf("x-api-key: %s", t.AdminToken),
+		fmt.Sprintf("http://localhost:8000%s", path))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(output), result); err != nil {
+		return fmt.Errorf("failed to parse output as JSON: %#v", output)
+	}
+	return nil
+}
+
+func (t *Service) GetNodeConfig(ctx context.Context) (*NodeConfig, error) {
+	var configs []NodeConfig
+	if err := t.apiGet(ctx, "/config", &configs); err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no node configured")
+	}
+	return &configs[0], nil
+}
+
+func (t *Service) GetChannels(ctx context.Context, node *NodeConfig) ([]Channel, error) {
+	var addresses []string
+	if err := t.apiGet(ctx, fmt.Sprintf("/%s/channels", node.PublicIdentifier), &addresses); err != nil {
+		return nil, err
+	}
+	var result []Channel
+	for _, addr := range addresses {
+		var c Channel
+		if err := t.apiGet(ctx, fmt.Sprintf("/%s/channels/%s", node.PublicIdentifier, addr), &c); err != nil {
+			return nil, fmt.Errorf("get channel %s: %w", addr, err)
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// BalanceOf returns the balances of address and its counterparty in asset
+func (t *Channel) BalanceOf(asset string, address string) (local *big.Int, remote *big.Int) {
+	local = big.NewInt(0)
+	remote = big.NewInt(0)
+	for i, id := range t.AssetIds {
+		if !strings.EqualFold(id, asset) || i >= len(t.Balances) {
+			continue
+		}
+		b := t.Balances[i]
+		for j, to := range b.To {
+			if j >= len(b.Amount) {
+				continue
+			}
+			amount, ok := new(big.Int).SetString(b.Amount[j], 10)
+			if !ok {
+				continue
+			}
+			if strings.EqualFold(to, address) {
+				local.Add(local, amount)
+			} else {
+				remote.Add(remote, amount)
+			}
+		}
+	}
+	return local, remote
+}
+
+// Counterparty returns the public identifier of the other party of the node
+func (t *Channel) Counterparty(node *NodeConfig) string {
+	if t.AliceIdentifier == node.PublicIdentifier {
+		return t.BobIdentifier
+	}
+	return t.AliceIdentifier
+}
+
+// Collateral sums the balances of the counterparties of the node in asset
+func Collateral(channels []Channel, node *NodeConfig, asset string) *big.Int {
+	total := big.NewInt(0)
+	for _, c := range channels {
+		_, remote := c.BalanceOf(asset, node.SignerAddress)
+		total.Add(total, remote)
+	}
+	return total
+}
+
+// IsMessagingReachable checks whether the messaging service is reachable from
+// the container. The node doesn't report its own messaging connection.
+func (t *Service) IsMessagingReachable(ctx context.Context) bool {
+	output, err := t.Exec(ctx, "curl", "-s", "-o", "/dev/null", "-w", "%{http_code}", fmt.Sprintf("%s/ping", MessagingUrl))
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(output) == "200"
+}
+
+func formatEther(wei *big.Int) string {
+	f := new(big.Float).SetInt(wei)
+	f.Quo(f, big.NewFloat(1e18))
+	return f.Text('f', 4)
+}
+
+// getStatusDetails returns the status details checked within
+// StatusDetailsTtl or checks them again
+func (t *Service) getStatusDetails(ctx context.Context) (string, error) {
+	t.detailsMu.Lock()
+	defer t.detailsMu.Unlock()
+	if t.details != "" && time.Since(t.detailsChecked) < StatusDetailsTtl {
+		return t.details, nil
+	}
+	details, err := t.checkStatusDetails(ctx)
+	if err != nil {
+		return "", err
+	}
+	t.details = details
+	t.detailsChecked = time.Now()
+	return details, nil
+}
+
+// checkStatusDetails summarizes the channels, ETH collateral and messaging
+// reachability of the vector node
+func (t *Service) checkStatusDetails(ctx context.Context) (string, error) {
+	node, err := t.GetNodeConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get config: %w", err)
+	}
+	channels, err := t.GetChannels(ctx, node)
+	if err != nil {
+		return "", fmt.Errorf("get channels: %w", err)
+	}
+	details := fmt.Sprintf("%d channels, %s ETH collateral", len(channels), formatEther(Collateral(channels, node, EthAssetId)))
+	if !t.IsMessagingReachable(ctx) {
+		details += ", messaging unreachable"
+	}
+	return details, nil
+}
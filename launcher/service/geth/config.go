@@ -11,14 +11,16 @@ type BaseConfig = base.Config
 type Config struct {
 	BaseConfig
 
-	Mode                string `usage:"%(name)s service mode"`
-	Rpcscheme           string `usage:"External %(name)s RPC scheme (http, https)"`
-	Rpchost             string `usage:"External %(name)s RPC hostname"`
-	Rpcport             uint16 `usage:"External %(name)s RPC port"`
-	InfuraProjectId     string `usage:"Infura %(name)s provider project ID"`
-	InfuraProjectSecret string `usage:"Infura %(name)s provider project secret"`
-	Cache               string `usage:"%(name)s cache size"`
-	AncientChaindataDir string `usage:"Specify the container's volume mapping ancient chaindata directory. Can be located on a slower HDD."`
+	Mode                string   `usage:"%(name)s service mode"`
+	Rpcscheme           string   `usage:"External %(name)s RPC scheme (http, https)"`
+	Rpchost             string   `usage:"External %(name)s RPC hostname"`
+	Rpcport             uint16   `usage:"External %(name)s RPC port"`
+	InfuraProjectId     string   `usage:"Infura %(name)s provider project ID"`
+	InfuraProjectSecret string   `usage:"Infura %(name)s provider project secret"`
+	Cache               string   `usage:"Native %(name)s cache size in MiB"`
+	AncientChaindataDir string   `usage:"Specify the container's volume mapping ancient chaindata directory. Can be located on a slower HDD."`
+	Providers           []string `usage:"Ordered Ethereum providers of connext: native, external, infura, light or JSON-RPC URLs"`
+	SkipUnresolvedProbe bool     `usage:"Skip probing Ethereum providers whose host cannot be resolved from this machine instead of counting them as unhealthy"`
 }
 
 func (t *Service) GetDefaultConfig() interface{} {
@@ -27,14 +29,17 @@ func (t *Service) GetDefaultConfig() interface{} {
 	if network == types.Mainnet {
 		image = "opendexnetwork/geth:1.9.24"
 	} else {
-		image = "opendexnetwork/arby:latest"
+		image = "opendexnetwork/geth:latest"
 	}
 	return &Config{
 		BaseConfig: BaseConfig{
-			Image:    t.Base.GetBranchImage(image),
-			Disabled: true,
+			Image: t.Base.GetBranchImage(image),
+			// the node container only runs in the native mode, which
+			// Apply enforces
+			Disabled: false,
 			Dir:      filepath.Join(t.Context.GetDataDir(), t.Name),
 		},
-		Mode: Light,
+		Mode:      Light,
+		Providers: []string{},
 	}
 }
@@ -0,0 +1,63 @@
+package geth
+
+import (
+	"context"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"github.com/opendexnetwork/opendex-docker/launcher/utils"
+	"strings"
+)
+
+// ChainIds are the Ethereum chain IDs of the launcher networks
+var ChainIds = map[types.Network]uint64{
+	types.Mainnet: 1,
+	types.Testnet: 4, // Rinkeby
+}
+
+// Probe health-checks the Ethereum providers before the services depending
+// on them are started. Unhealthy providers are only reported as long as
+// connext can fall back to a healthy one. The native node is not running yet
+// so it is skipped.
+func (t *Service) Probe(ctx context.Context) error {
+	if t.Context.GetNetwork() == types.Simnet {
+		return nil
+	}
+
+	var errs []string
+	healthy := false
+	for _, p := range t.Providers {
+		if p.Host == "" {
+			errs = append(errs, fmt.Sprintf("%s provider host is required", t.Name))
+			continue
+		}
+		if p.Host == t.Name {
+			healthy = true
+			continue
+		}
+		if !utils.Resolvable(ctx, p.Host) {
+			if !t.SkipUnresolvedProbe {
+				err := fmt.Errorf("%s cannot be resolved, use --%s.skip-unresolved-probe if only the containers can resolve it", p.Host, t.Name)
+				t.Logger.Warnf("Ethereum provider %s is unhealthy: %s", p.Display(), err)
+				errs = append(errs, fmt.Sprintf("%s: %s", p.Display(), err))
+				continue
+			}
+			// connext may still reach it through the docker network
+			t.Logger.Warnf("Skipping the probe of Ethereum provider %s, %s cannot be resolved from the host", p.Display(), p.Host)
+			healthy = true
+			continue
+		}
+		err := t.checkProvider(ctx, p)
+		t.setProviderHealth(p, err)
+		if err != nil {
+			t.Logger.Warnf("Ethereum provider %s is unhealthy: %s", p.Display(), err)
+			errs = append(errs, fmt.Sprintf("%s: %s", p.Display(), err))
+			continue
+		}
+		healthy = true
+	}
+
+	if !healthy {
+		return fmt.Errorf("no healthy Ethereum provider: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package geth
+
+import (
+	"context"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProviderHealthTtl is how long status polls reuse the health of a provider
+// instead of checking it again
+const ProviderHealthTtl = time.Minute
+
+type providerHealth struct {
+	err     error
+	checked time.Time
+}
+
+// Display returns the provider address without the path and credentials
+// which may contain API keys
+func (t RpcParams) Display() string {
+	if t.Port == 0 {
+		return fmt.Sprintf("%s://%s", t.Scheme, t.Host)
+	}
+	return fmt.Sprintf("%s://%s:%d", t.Scheme, t.Host, t.Port)
+}
+
+// parseProvider parses a provider entry which is either a geth mode (native,
+// external, infura, light) or a JSON-RPC URL (e.g. an Alchemy URL with its
+// API key in the path)
+func (t *Service) parseProvider(provider string, c *Config) (RpcParams, error) {
+	switch Mode(provider) {
+	case Native:
+		if t.Mode != Native {
+			return RpcParams{}, fmt.Errorf("the native provider requires %s native mode", t.Name)
+		}
+		fallthrough
+	case External, Infura, Light:
+		return t.getModeRpcParams(Mode(provider), c)
+	}
+
+	u, err := url.Parse(provider)
+	if err != nil {
+		return RpcParams{}, fmt.Errorf("invalid provider URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return RpcParams{}, fmt.Errorf("unsupported provider URL scheme: %s", u.Scheme)
+	}
+	params := RpcParams{
+		Type:   "JSON-RPC",
+		Scheme: u.Scheme,
+		Host:   u.Hostname(),
+		Path:   u.RequestURI(),
+	}
+	if params.Path == "/" {
+		params.Path = ""
+	}
+	if port := u.Port(); port != "" {
+		p, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return RpcParams{}, fmt.Errorf("invalid provider port: %s", port)
+		}
+		params.Port = uint16(p)
+	}
+	if password, ok := u.User.Password(); ok {
+		params.Secret = password
+	}
+	// API keys are usually embedded in the URL
+	log.RegisterSecret(provider)
+	if params.Secret != "" {
+		log.RegisterSecret(params.Secret)
+	}
+	return params, nil
+}
+
+// getProviders resolves the configured providers. The provider of the geth
+// mode is used alone when no providers are configured.
+func (t *Service) getProviders(c *Config) ([]RpcParams, error) {
+	if len(c.Providers) == 0 {
+		return []RpcParams{t.RpcParams}, nil
+	}
+	var result []RpcParams
+	for _, p := range c.Providers {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		params, err := t.parseProvider(p, c)
+		if err != nil {
+			return nil, fmt.Errorf("%s provider %q: %w", t.Name, p, err)
+		}
+		result = append(result, params)
+	}
+	if len(result) == 0 {
+		return []RpcParams{t.RpcParams}, nil
+	}
+	return result, nil
+}
+
+// GetProvidersUri returns the providers as a comma-separated list which
+// connext uses as fallback providers in order. Providers which failed their
+// latest check are moved to the end.
+func (t *Service) GetProvidersUri() string {
+	var healthy, failed []string
+	for _, p := range t.Providers {
+		if t.isFailed(p) {
+			failed = append(failed, p.ToUri())
+		} else {
+			healthy = append(healthy, p.ToUri())
+		}
+	}
+	return strings.Join(append(healthy, failed...), ",")
+}
+
+// isFailed tells whether the latest check of the provider failed
+func (t *Service) isFailed(p RpcParams) bool {
+	t.healthMu.Lock()
+	defer t.healthMu.Unlock()
+	h, ok := t.health[p.ToUri()]
+	return ok && h.err != nil
+}
+
+// checkProvider makes sure the provider is on the chain of the network and
+// synced. The native node is checked inside its container.
+func (t *Service) checkProvider(ctx context.Context, p RpcParams) error {
+	if p.Host == t.Name {
+		progress, _, err := t.getNodeSync(ctx)
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			return fmt.Errorf("still syncing (%d/%d)", progress.CurrentBlock, progress.HighestBlock)
+		}
+		return nil
+	}
+
+	chainId, err := p.GetChainId(ctx)
+	if err != nil {
+		return err
+	}
+	network := t.Context.GetNetwork()
+	if expected, ok := ChainIds[network]; ok && chainId != expected {
+		return fmt.Errorf("on chain %d but %s requires chain %d", chainId, network, expected)
+	}
+
+	progress, err := p.GetSyncProgress(ctx)
+	if err != nil {
+		return err
+	}
+	if progress != nil {
+		return fmt.Errorf("still syncing (%d/%d)", progress.CurrentBlock, progress.HighestBlock)
+	}
+	return nil
+}
+
+// setProviderHealth records the result of a provider check
+func (t *Service) setProviderHealth(p RpcParams, err error) {
+	t.healthMu.Lock()
+	defer t.healthMu.Unlock()
+	if t.health == nil {
+		t.health = make(map[string]providerHealth)
+	}
+	t.health[p.ToUri()] = providerHealth{err: err, checked: time.Now()}
+}
+
+// getProviderHealth checks the provider unless it was checked within
+// ProviderHealthTtl, each check may take the RPC timeout
+func (t *Service) getProviderHealth(ctx context.Context, p RpcParams) error {
+	t.healthMu.Lock()
+	h, ok := t.health[p.ToUri()]
+	t.healthMu.Unlock()
+	if ok && time.Since(h.checked) < ProviderHealthTtl {
+		return h.err
+	}
+	err := t.checkProvider(ctx, p)
+	t.setProviderHealth(p, err)
+	return err
+}
+
+// getActiveProvider returns the first healthy provider which connext falls
+// back to, and the number of unhealthy providers
+func (t *Service) getActiveProvider(ctx context.Context) (*RpcParams, int) {
+	var active *RpcParams
+	failed := 0
+	for i, p := range t.Providers {
+		if err := t.getProviderHealth(ctx, p); err != nil {
+			t.Logger.Debugf("Provider %s is unhealthy: %s", p.Display(), err)
+			failed++
+			continue
+		}
+		if active == nil {
+			active = &t.Providers[i]
+		}
+	}
+	return active, failed
+}
+
+// describeProviders describes the active provider of connext
+func describeProviders(active *RpcParams, failed int) string {
+	if active == nil {
+		return fmt.Sprintf("no healthy Ethereum provider (%d failed)", failed)
+	}
+	if failed > 0 {
+		return fmt.Sprintf("provider %s, %d failed", active.Display(), failed)
+	}
+	return fmt.Sprintf("provider %s", active.Display())
+}
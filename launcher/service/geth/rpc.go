@@ -0,0 +1,126 @@
+package geth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type rpcRequest struct {
+	JsonRpc string        `json:"jsonrpc"`
+	Id      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type RpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (t *RpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", t.Code, t.Message)
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RpcError       `json:"error"`
+}
+
+var rpcClient = &http.Client{Timeout: 10 * time.Second}
+
+// call invokes an Ethereum JSON-RPC method of the provider
+func (t RpcParams) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcRequest{JsonRpc: "2.0", Id: 1, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", t.ToUri(), bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := rpcClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("[http %d] invalid credentials", resp.StatusCode)
+	}
+
+	var r rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("[http %d] decode response: %w", resp.StatusCode, err)
+	}
+	if r.Error != nil {
+		return r.Error
+	}
+	if err := json.Unmarshal(r.Result, result); err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
+	return nil
+}
+
+func parseQuantity(s string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+}
+
+func (t RpcParams) GetChainId(ctx context.Context) (uint64, error) {
+	var result string
+	if err := t.call(ctx, "eth_chainId", &result); err != nil {
+		return 0, err
+	}
+	return parseQuantity(result)
+}
+
+type SyncProgress struct {
+	CurrentBlock uint64
+	HighestBlock uint64
+}
+
+// GetSyncProgress returns nil when the node is not syncing
+func (t RpcParams) GetSyncProgress(ctx context.Context) (*SyncProgress, error) {
+	var result json.RawMessage
+	if err := t.call(ctx, "eth_syncing", &result); err != nil {
+		return nil, err
+	}
+	var syncing bool
+	if err := json.Unmarshal(result, &syncing); err == nil {
+		return nil, nil
+	}
+	var r struct {
+		CurrentBlock string `json:"currentBlock"`
+		HighestBlock string `json:"highestBlock"`
+	}
+	if err := json.Unmarshal(result, &r); err != nil {
+		return nil, fmt.Errorf("decode eth_syncing result: %w", err)
+	}
+	current, err := parseQuantity(r.CurrentBlock)
+	if err != nil {
+		return nil, fmt.Errorf("parse currentBlock: %w", err)
+	}
+	highest, err := parseQuantity(r.HighestBlock)
+	if err != nil {
+		return nil, fmt.Errorf("parse highestBlock: %w", err)
+	}
+	return &SyncProgress{CurrentBlock: current, HighestBlock: highest}, nil
+}
+
+func (t RpcParams) GetPeerCount(ctx context.Context) (uint64, error) {
+	var result string
+	if err := t.call(ctx, "net_peerCount", &result); err != nil {
+		return 0, err
+	}
+	return parseQuantity(result)
+}
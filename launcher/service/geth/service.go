@@ -1,9 +1,17 @@
 package geth
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/log"
+	"github.com/opendexnetwork/opendex-docker/launcher/service"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 type Base = base.Service
@@ -23,13 +31,20 @@ type RpcParams struct {
 	Host   string `json:"host"`
 	Path   string `json:"path"`
 	Port   uint16 `json:"port"`
+	// Secret is the basic auth password of the provider (e.g. the Infura project secret)
+	Secret string `json:"-"`
 }
 
 func (t RpcParams) ToUri() string {
-	if t.Port == 0 {
-		return fmt.Sprintf("%s://%s%s", t.Scheme, t.Host, t.Path)
+	u := url.URL{Scheme: t.Scheme, Host: t.Host}
+	if t.Port != 0 {
+		u.Host = fmt.Sprintf("%s:%d", t.Host, t.Port)
 	}
-	return fmt.Sprintf("%s://%s:%d%s", t.Scheme, t.Host, t.Port, t.Path)
+	if t.Secret != "" {
+		u.User = url.UserPassword("", t.Secret)
+	}
+	// Path is already escaped and may carry a query
+	return u.String() + t.Path
 }
 
 type Service struct {
@@ -37,6 +52,15 @@ type Service struct {
 
 	Mode      Mode
 	RpcParams RpcParams
+	// Providers are the Ethereum providers of connext in the order of preference
+	Providers []RpcParams
+	// SkipUnresolvedProbe trusts providers which cannot be resolved from the
+	// host instead of counting them as unhealthy
+	SkipUnresolvedProbe bool
+
+	healthMu sync.Mutex
+	// health caches the latest check of each provider by URI
+	health map[string]providerHealth
 }
 
 func New(ctx types.Context, name string) (*Service, error) {
@@ -57,57 +81,108 @@ func (t *Service) Apply(cfg interface{}) error {
 		return err
 	}
 
-	t.RpcParams.Type = "JSON-RPC"
-
 	if t.Context.GetNetwork() == types.Simnet {
-		// simnet PoA eth provider
-		t.RpcParams.Scheme = "http"
-		t.RpcParams.Host = "35.234.110.95"
-		t.RpcParams.Port = 8545
+		// simnet PoA eth provider, there is no node container
+		t.Disabled = true
+		t.RpcParams = RpcParams{
+			Type:   "JSON-RPC",
+			Scheme: "http",
+			Host:   "35.234.110.95",
+			Port:   8545,
+		}
 	} else {
 		t.Mode = Mode(c.Mode)
-		switch t.Mode {
-		case External:
-			t.RpcParams.Scheme = "http"
-			t.RpcParams.Host = c.Rpchost
-			t.RpcParams.Port = c.Rpcport
-		case Infura:
-			proj := c.InfuraProjectId
-			network := t.Context.GetNetwork()
-			if network == types.Mainnet {
-				t.RpcParams.Scheme = "https"
-				t.RpcParams.Host = "mainnet.infura.io"
-				t.RpcParams.Path = fmt.Sprintf("/v3/%s", proj)
-			} else if network == types.Testnet {
-				t.RpcParams.Scheme = "https"
-				t.RpcParams.Host = "rinkeyby.infura.io"
-				t.RpcParams.Path = fmt.Sprintf("/v3/%s", proj)
+		params, err := t.getModeRpcParams(t.Mode, c)
+		if err != nil {
+			return err
+		}
+		t.RpcParams = params
+
+		if t.Mode == Native {
+			t.Volumes = append(t.Volumes, fmt.Sprintf("%s:/root/.ethereum", t.DataDir))
+			if c.AncientChaindataDir != "" {
+				t.Volumes = append(t.Volumes, fmt.Sprintf("%s:/root/.ethereum-ancient-chaindata", c.AncientChaindataDir))
+				t.Environment["CUSTOM_ANCIENT_CHAINDATA"] = "true"
 			} else {
-				return fmt.Errorf("no Infura Ethereum prodiver for %s", network)
+				t.Environment["CUSTOM_ANCIENT_CHAINDATA"] = "false"
 			}
-		case Light:
-			network := t.Context.GetNetwork()
-			if network == types.Mainnet {
-				t.RpcParams.Scheme = "http"
-				t.RpcParams.Host = "eth.kilrau.com"
-				t.RpcParams.Port = 41007
-			} else if network == types.Testnet {
-				t.RpcParams.Scheme = "http"
-				t.RpcParams.Host = "eth.kilrau.com"
-				t.RpcParams.Port = 52041
-			} else {
-				return fmt.Errorf("no Light ethereum provider for %s", network)
+			if c.Cache != "" {
+				if _, err := strconv.ParseUint(c.Cache, 10, 32); err != nil {
+					return fmt.Errorf("invalid %s cache size (MiB): %s", t.Name, c.Cache)
+				}
+				t.Environment["CACHE"] = c.Cache
 			}
-		case Native:
-			t.RpcParams.Scheme = "http"
-			t.RpcParams.Host = "geth"
-			t.RpcParams.Port = 8545
 		}
+
+		// only the native mode runs a node container
+		if t.Mode != Native {
+			t.Disabled = true
+		}
+	}
+
+	providers, err := t.getProviders(c)
+	if err != nil {
+		return err
 	}
+	t.Providers = providers
+	t.SkipUnresolvedProbe = c.SkipUnresolvedProbe
 
 	return nil
 }
 
+// getModeRpcParams returns the provider of a geth mode
+func (t *Service) getModeRpcParams(mode Mode, c *Config) (RpcParams, error) {
+	params := RpcParams{Type: "JSON-RPC"}
+	network := t.Context.GetNetwork()
+
+	switch mode {
+	case External:
+		params.Scheme = "http"
+		if c.Rpcscheme != "" {
+			params.Scheme = c.Rpcscheme
+		}
+		params.Host = c.Rpchost
+		params.Port = c.Rpcport
+	case Infura:
+		proj := c.InfuraProjectId
+		if network == types.Mainnet {
+			params.Scheme = "https"
+			params.Host = "mainnet.infura.io"
+			params.Path = fmt.Sprintf("/v3/%s", proj)
+		} else if network == types.Testnet {
+			params.Scheme = "https"
+			params.Host = "rinkeby.infura.io"
+			params.Path = fmt.Sprintf("/v3/%s", proj)
+		} else {
+			return params, fmt.Errorf("no Infura Ethereum prodiver for %s", network)
+		}
+		if c.InfuraProjectSecret != "" {
+			params.Secret = c.InfuraProjectSecret
+			log.RegisterSecret(c.InfuraProjectSecret)
+		}
+	case Light:
+		if network == types.Mainnet {
+			params.Scheme = "http"
+			params.Host = "eth.kilrau.com"
+			params.Port = 41007
+		} else if network == types.Testnet {
+			params.Scheme = "http"
+			params.Host = "eth.kilrau.com"
+			params.Port = 52041
+		} else {
+			return params, fmt.Errorf("no Light ethereum provider for %s", network)
+		}
+	case Native:
+		params.Scheme = "http"
+		params.Host = t.Name
+		params.Port = 8545
+	default:
+		return params, fmt.Errorf("invalid %s mode: %s", t.Name, mode)
+	}
+
+	return params, nil
+}
+
 func (t *Service) GetRpcParams() (interface{}, error) {
 	return t.RpcParams, nil
 }
@@ -115,3 +190,90 @@ func (t *Service) GetRpcParams() (interface{}, error) {
 func (t *Service) GetMode() string {
 	return string(t.Mode)
 }
+
+func (t *Service) GetStatus(ctx context.Context) (string, error) {
+	if t.Mode == Native {
+		status, err := t.Base.GetStatus(ctx)
+		if err != nil {
+			return "", err
+		}
+		if status != "Container running" {
+			return status, nil
+		}
+		status, err = t.getNodeStatus(ctx)
+		if err != nil || len(t.Providers) < 2 {
+			return status, err
+		}
+		// connext falls back to the other providers while the node syncs
+		return fmt.Sprintf("%s (%s)", status, describeProviders(t.getActiveProvider(ctx))), nil
+	}
+
+	active, failed := t.getActiveProvider(ctx)
+	if active == nil {
+		return fmt.Sprintf("No healthy Ethereum provider (%d failed)", failed), nil
+	}
+	return fmt.Sprintf("Ready (%s)", describeProviders(active, failed)), nil
+}
+
+// nodeStatusScript prints the sync progress and the peer count of the node as
+// JSON in the geth console
+const nodeStatusScript = "JSON.stringify({syncing: eth.syncing, peers: net.peerCount})"
+
+// getNodeSync queries the native node in its container, the host can neither
+// resolve its name nor reach the unpublished RPC port
+func (t *Service) getNodeSync(ctx context.Context) (*SyncProgress, uint64, error) {
+	output, err := t.Exec(ctx, "geth", "attach", "--exec", nodeStatusScript, "http://127.0.0.1:8545")
+	if err != nil {
+		return nil, 0, err
+	}
+	output = strings.TrimSpace(output)
+	// the console quotes the returned string
+	var unquoted string
+	if err := json.Unmarshal([]byte(output), &unquoted); err == nil {
+		output = unquoted
+	}
+	var r struct {
+		Syncing json.RawMessage `json:"syncing"`
+		Peers   uint64          `json:"peers"`
+	}
+	if err := json.Unmarshal([]byte(output), &r); err != nil {
+		return nil, 0, fmt.Errorf("decode node status %q: %w", output, err)
+	}
+	var syncing bool
+	if err := json.Unmarshal(r.Syncing, &syncing); err == nil {
+		return nil, r.Peers, nil
+	}
+	var progress SyncProgress
+	if err := json.Unmarshal(r.Syncing, &progress); err != nil {
+		return nil, 0, fmt.Errorf("decode sync progress: %w", err)
+	}
+	return &progress, r.Peers, nil
+}
+
+// getNodeStatus reports the sync progress and peers of the native node
+func (t *Service) getNodeStatus(ctx context.Context) (string, error) {
+	progress, peers, err := t.getNodeSync(ctx)
+	if err != nil {
+		if err, ok := err.(service.ErrExec); ok {
+			// the RPC server is not up yet, e.g. "Unable to attach to remote geth: ... connection refused"
+			if strings.Contains(err.Output, "Unable to attach") || strings.Contains(err.Output, "connection refused") {
+				return "Starting...", nil
+			}
+		}
+		return "", err
+	}
+
+	if progress != nil {
+		var p float64
+		if progress.HighestBlock > 0 {
+			p = float64(progress.CurrentBlock) / float64(progress.HighestBlock) * 100
+		}
+		return fmt.Sprintf("Syncing %.2f%% (%d/%d), %d peers", p, progress.CurrentBlock, progress.HighestBlock, peers), nil
+	}
+
+	if peers == 0 {
+		return "Waiting for peers", nil
+	}
+
+	return "Ready", nil
+}
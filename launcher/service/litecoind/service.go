@@ -3,10 +3,17 @@ package litecoind
 import (
 	"github.com/opendexnetwork/opendex-docker/launcher/service/bitcoind"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"strings"
 )
 
 type Base = bitcoind.Service
 
+// LitecoinGenesis are the genesis block hashes of the Litecoin networks
+var LitecoinGenesis = map[string]string{
+	"main": "12a765e31ffd4059bada1e25190f6e98c99d9714d334efa41a195a7e7e04bfe2",
+	"test": "4966625a4b2851d9fdee139e56211a0d88575f59ed816ff5e6a63deb4e3e29a0",
+}
+
 type Service struct {
 	*Base
 }
@@ -17,6 +24,9 @@ func New(ctx types.Context, name string) (*Service, error) {
 		return nil, err
 	}
 	s.ContainerDataDir = "/root/.litecoind"
+	s.ZmqPorts = [2]uint16{29332, 29333}
+	s.Cli = "litecoin-cli"
+	s.Genesis = LitecoinGenesis
 
 	return &Service{
 		Base: s,
@@ -37,6 +47,20 @@ func (t *Service) Apply(cfg interface{}) error {
 		} else {
 			t.RpcParams.Port = 19332
 		}
+		// the litecoind image builds its own command line and takes the
+		// RPC credentials from the environment
+		t.Command = []string{}
+		t.Environment["DATA_DIR"] = t.ContainerDataDir
+		t.Environment["RPC_USER"] = t.RpcParams.Username
+		t.SetSecret("RPC_PASSWORD", t.RpcParams.Password)
+		if c.Txindex {
+			t.Environment["TXINDEX"] = "1"
+		} else {
+			t.Environment["TXINDEX"] = "0"
+		}
+		if opts := bitcoind.NodeOptions(&c.BaseConfig); len(opts) > 0 {
+			t.Environment["EXTRA_OPTS"] = strings.Join(opts, " ")
+		}
 	}
 
 	return nil
@@ -0,0 +1,79 @@
+package lnd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// lncli runs an lncli command and decodes its JSON output into result
+func (t *Service) lncli(ctx context.Context, result interface{}, args ...string) error {
+	args = append([]string{"-n", string(t.Context.GetNetwork()), "-c", string(t.Chain)}, args...)
+	output, err := t.Exec(ctx, "lncli", args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(output), result); err != nil {
+		return fmt.Errorf("failed to parse output as JSON: %#v", output)
+	}
+	return nil
+}
+
+type WalletBalance struct {
+	TotalBalance       int64 `json:"total_balance,string"`
+	ConfirmedBalance   int64 `json:"confirmed_balance,string"`
+	UnconfirmedBalance int64 `json:"unconfirmed_balance,string"`
+}
+
+func (t *Service) GetWalletBalance(ctx context.Context) (*WalletBalance, error) {
+	var result WalletBalance
+	if err := t.lncli(ctx, &result, "walletbalance"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type Channel struct {
+	Active        bool   `json:"active"`
+	RemotePubkey  string `json:"remote_pubkey"`
+	ChannelPoint  string `json:"channel_point"`
+	Capacity      int64  `json:"capacity,string"`
+	LocalBalance  int64  `json:"local_balance,string"`
+	RemoteBalance int64  `json:"remote_balance,string"`
+}
+
+func (t *Service) ListChannels(ctx context.Context) ([]Channel, error) {
+	var result struct {
+		Channels []Channel `json:"channels"`
+	}
+	if err := t.lncli(ctx, &result, "listchannels"); err != nil {
+		return nil, err
+	}
+	return result.Channels, nil
+}
+
+type PendingChannel struct {
+	RemoteNodePub string `json:"remote_node_pub"`
+	ChannelPoint  string `json:"channel_point"`
+	Capacity      int64  `json:"capacity,string"`
+	LocalBalance  int64  `json:"local_balance,string"`
+	RemoteBalance int64  `json:"remote_balance,string"`
+}
+
+// ListPendingOpenChannels returns the channels waiting for their funding
+// transactions to confirm
+func (t *Service) ListPendingOpenChannels(ctx context.Context) ([]PendingChannel, error) {
+	var result struct {
+		PendingOpenChannels []struct {
+			Channel PendingChannel `json:"channel"`
+		} `json:"pending_open_channels"`
+	}
+	if err := t.lncli(ctx, &result, "pendingchannels"); err != nil {
+		return nil, err
+	}
+	var channels []PendingChannel
+	for _, c := range result.PendingOpenChannels {
+		channels = append(channels, c.Channel)
+	}
+	return channels, nil
+}
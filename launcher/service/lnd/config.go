@@ -21,6 +21,9 @@ type Config struct {
 	Mode string
 
 	PreserveConfig bool
+
+	DisableTor bool   `usage:"Disable tor and run %(name)s in clearnet-only mode"`
+	TorProxy   string `usage:"External tor SOCKS proxy (host:port) of %(name)s instead of the bundled tor"`
 }
 
 func (t *Service) GetDefaultConfig() interface{} {
@@ -55,5 +58,7 @@ func (t *Service) GetDefaultConfig() interface{} {
 		},
 		Mode:           string(Native),
 		PreserveConfig: false,
+		DisableTor:     false,
+		TorProxy:       "",
 	}
 }
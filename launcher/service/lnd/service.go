@@ -2,12 +2,11 @@ package lnd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker/launcher/service"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/bitcoind"
-	"github.com/opendexnetwork/opendex-docker/launcher/service/litecoind"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/tor"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
 	"regexp"
 	"strconv"
@@ -28,11 +27,14 @@ var (
 	reNeutrinoSyncingBegin = regexp.MustCompile(`^.*Syncing to block height (\d+) from peer.*$`)
 	reNeutrinoSyncingEnd   = regexp.MustCompile(`^.*Fully caught up with cfheaders at height (\d+), waiting at tip for new blocks.*$`)
 	reNeutrinoSyncing      = regexp.MustCompile(`^.*Fetching set of checkpointed cfheaders filters from height=(\d+).*$`)
+	reSyncingText          = regexp.MustCompile(`^Syncing \d+\.\d+% \((\d+)/(\d+)\)`)
 )
 
 type Service struct {
 	*Base
-	Chain Chain
+	Chain    Chain
+	TorMode  tor.Mode
+	TorProxy string
 }
 
 func New(ctx types.Context, name string, chain Chain) (*Service, error) {
@@ -53,14 +55,9 @@ type Info struct {
 }
 
 func (t *Service) GetInfo(ctx context.Context) (*Info, error) {
-	output, err := t.Exec(ctx, "lncli", "-n", string(t.Context.GetNetwork()), "-c", string(t.Chain), "getinfo")
-	if err != nil {
-		return nil, err
-	}
 	var info Info
-	err = json.Unmarshal([]byte(output), &info)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse output as JSON: %#v", output)
+	if err := t.lncli(ctx, &info, "getinfo"); err != nil {
+		return nil, err
 	}
 	return &info, nil
 }
@@ -151,6 +148,24 @@ func (t *Service) getSyncingText(synced uint64, total uint64) string {
 	return fmt.Sprintf("Syncing %.2f%% (%d/%d)", p, synced, total)
 }
 
+// ParseSyncingText extracts the synced and total heights from a status
+// generated by getSyncingText
+func ParseSyncingText(status string) (synced uint64, total uint64, ok bool) {
+	m := reSyncingText.FindStringSubmatch(status)
+	if m == nil {
+		return 0, 0, false
+	}
+	synced, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	total, err = strconv.ParseUint(m[2], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return synced, total, true
+}
+
 func (t *Service) getSyncedHeight(ctx context.Context) (uint, error) {
 	startedAt, err := t.Base.GetStartedAt(ctx)
 	if err != nil {
@@ -173,31 +188,47 @@ func (t *Service) getSyncedHeight(ctx context.Context) (uint, error) {
 	return 0, nil
 }
 
+// getBackendService returns the bitcoind or litecoind service backing this lnd
+func (t *Service) getBackendService() (types.Service, error) {
+	switch t.Chain {
+	case Bitcoin:
+		return t.Context.GetService("bitcoind")
+	case Litecoin:
+		return t.Context.GetService("litecoind")
+	}
+	return nil, fmt.Errorf("unsupported chain: %s", t.Chain)
+}
+
 func (t *Service) UseNeutrino() bool {
 	if t.Context.GetNetwork() == types.Simnet {
 		return true
 	}
-	switch t.Chain {
-	case Bitcoin:
-		s, err := t.Context.GetService("bitcoind")
-		if err != nil {
-			panic(err)
-		}
-		if s.GetMode() == "neutrino" || s.GetMode() == "light" {
-			return true
-		}
-	case Litecoin:
-		s, err := t.Context.GetService("litecoind")
-		if err != nil {
-			panic(err)
-		}
-		if s.GetMode() == "neutrino" || s.GetMode() == "light" {
-			return true
-		}
+	s, err := t.getBackendService()
+	if err != nil {
+		panic(err)
+	}
+	if s.GetMode() == bitcoind.Neutrino || s.GetMode() == bitcoind.Light {
+		return true
 	}
 	return false
 }
 
+// Backend describes the chain backend of lnd, e.g. "neutrino", "bitcoind" or
+// "external litecoind"
+func (t *Service) Backend() string {
+	if t.UseNeutrino() {
+		return "neutrino"
+	}
+	s, err := t.getBackendService()
+	if err != nil {
+		panic(err)
+	}
+	if s.GetMode() == bitcoind.External {
+		return "external " + s.GetName()
+	}
+	return s.GetName()
+}
+
 func (t *Service) GetLndPid(ctx context.Context) uint16 {
 	output, err := t.Exec(ctx, "pgrep", "lnd")
 	if err != nil {
@@ -212,6 +243,17 @@ func (t *Service) GetLndPid(ctx context.Context) uint16 {
 }
 
 func (t *Service) GetStatus(ctx context.Context) (string, error) {
+	status, err := t.getStatus(ctx)
+	if err != nil || status == "Ready" || strings.HasPrefix(status, "Container ") {
+		return status, err
+	}
+	if torStatus := t.getTorStatus(ctx); torStatus != "" {
+		return torStatus, nil
+	}
+	return status, nil
+}
+
+func (t *Service) getStatus(ctx context.Context) (string, error) {
 	status, err := t.Base.GetStatus(ctx)
 	if err != nil {
 		return "", err
@@ -230,6 +272,8 @@ func (t *Service) GetStatus(ctx context.Context) (string, error) {
 				if t.UseNeutrino() {
 					return t.getNeutrinoSyncingStatus(ctx)
 				}
+				// lnd doesn't sync from a full node backend before the wallet is created
+				return fmt.Sprintf("Waiting for wallet (%s backend)", t.Backend()), nil
 			}
 			if strings.Contains(err.Output, "open /root/.lnd/tls.cert: no such file or directory") {
 				return "Starting...", nil
@@ -267,8 +311,27 @@ func (t *Service) Apply(cfg interface{}) error {
 		t.Environment["PRESERVE_CONFIG"] = "false"
 	}
 
-	if t.Context.GetExternalIp() != "" {
-		t.Environment["EXTERNAL_IP"] = t.Context.GetExternalIp()
+	if ip := t.Context.GetExternalIp(); ip != "" {
+		t.Environment["EXTERNAL_IP"] = ip
+		// peers connect to the advertised address on the host
+		port := t.GetP2pPort()
+		t.Ports = append(t.Ports, fmt.Sprintf("%d:%d", port, port))
+	}
+
+	torMode, err := tor.GetMode(c.DisableTor, c.TorProxy)
+	if err != nil {
+		return fmt.Errorf("%s: %w", t.Name, err)
+	}
+	t.TorMode = torMode
+	t.TorProxy = c.TorProxy
+	switch torMode {
+	case tor.Disabled:
+		t.Environment["TOR_DISABLED"] = "true"
+		if t.Context.GetExternalIp() == "" {
+			t.Logger.Warnf("Tor is disabled and there is no external IP, %s is not reachable by peers", t.Name)
+		}
+	case tor.Proxy:
+		t.Environment["TOR_PROXY"] = c.TorProxy
 	}
 
 	network := t.Context.GetNetwork()
@@ -307,55 +370,43 @@ func (t *Service) Apply(cfg interface{}) error {
 			)
 		}
 	} else {
-		switch t.Chain {
-		case Bitcoin:
-			s, err := t.Context.GetService("bitcoind")
-			if err != nil {
-				return err
-			}
-			mode := s.(*bitcoind.Service).Mode
-			if mode == bitcoind.External {
-				params, err := s.GetRpcParams()
-				if err != nil {
-					return err
-				}
-				p := params.(bitcoind.RpcParams)
-				t.Environment["RPCHOST"] = p.Host
-				t.Environment["RPCPORT"] = fmt.Sprintf("%d", p.Port)
-				t.Environment["RPCUSER"] = p.Username
-				t.Environment["RPCPASS"] = p.Password
-				t.Environment["ZMQPUBRAWBLOCK"] = p.Zmqpubrawblock
-				t.Environment["ZMQPUBRAWTX"] = p.Zmqpubrawtx
-			} else {
-				t.Environment["NEUTRINO"] = "True"
-			}
-		case Litecoin:
-			s, err := t.Context.GetService("litecoind")
+		s, err := t.getBackendService()
+		if err != nil {
+			return err
+		}
+		mode := bitcoind.Mode(s.GetMode())
+		if mode == bitcoind.Native || mode == bitcoind.External {
+			params, err := s.GetRpcParams()
 			if err != nil {
 				return err
 			}
-			mode := s.(*litecoind.Service).Mode
-			if mode == bitcoind.External {
-				params, err := s.GetRpcParams()
-				if err != nil {
-					return err
-				}
-				p := params.(bitcoind.RpcParams)
-				t.Environment["RPCHOST"] = p.Host
-				t.Environment["RPCPORT"] = fmt.Sprintf("%d", p.Port)
-				t.Environment["RPCUSER"] = p.Username
-				t.Environment["RPCPASS"] = p.Password
-				t.Environment["ZMQPUBRAWBLOCK"] = p.Zmqpubrawblock
-				t.Environment["ZMQPUBRAWTX"] = p.Zmqpubrawtx
-			} else {
-				t.Environment["NEUTRINO"] = "True"
-			}
+			p := params.(bitcoind.RpcParams)
+			t.Environment["RPCHOST"] = fmt.Sprintf("%s:%d", p.Host, p.Port)
+			t.Environment["RPCPORT"] = fmt.Sprintf("%d", p.Port)
+			t.Environment["RPCUSER"] = p.Username
+			t.SetSecret("RPCPASS", p.Password)
+			t.Environment["ZMQPUBRAWBLOCK"] = p.Zmqpubrawblock
+			t.Environment["ZMQPUBRAWTX"] = p.Zmqpubrawtx
+		} else {
+			t.Environment["NEUTRINO"] = "True"
 		}
 	}
 
 	return nil
 }
 
+// GetP2pPort returns the port lnd listens on for peers
+func (t *Service) GetP2pPort() uint16 {
+	var port uint16 = 9735
+	if t.Chain == Litecoin {
+		port = 10735
+	}
+	if t.Context.GetNetwork() == types.Testnet {
+		port += 10000
+	}
+	return port
+}
+
 func (t *Service) GetRpcParams() (interface{}, error) {
 	var params = make(map[string]interface{})
 	params["type"] = "gRPC"
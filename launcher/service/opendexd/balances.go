@@ -0,0 +1,139 @@
+package opendexd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// cli runs an opendex-cli command with JSON output and decodes it into result
+func (t *Service) cli(ctx context.Context, result interface{}, args ...string) error {
+	output, err := t.Exec(ctx, "opendex-cli", append(args, "-j")...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(output), result); err != nil {
+		return errors.New(output)
+	}
+	return nil
+}
+
+// decodePairs decodes a protobuf map which opendex-cli prints as a list of
+// [key, value] pairs
+func decodePairs(pairs [][2]json.RawMessage, newValue func(key string) interface{}) error {
+	for _, pair := range pairs {
+		var key string
+		if err := json.Unmarshal(pair[0], &key); err != nil {
+			return fmt.Errorf("decode key: %w", err)
+		}
+		if err := json.Unmarshal(pair[1], newValue(key)); err != nil {
+			return fmt.Errorf("decode %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Balance amounts are in satoshis (1e-8 of a coin) for all currencies
+type Balance struct {
+	TotalBalance             uint64 `json:"totalBalance"`
+	ChannelBalance           uint64 `json:"channelBalance"`
+	PendingChannelBalance    uint64 `json:"pendingChannelBalance"`
+	InactiveChannelBalance   uint64 `json:"inactiveChannelBalance"`
+	WalletBalance            uint64 `json:"walletBalance"`
+	UnconfirmedWalletBalance uint64 `json:"unconfirmedWalletBalance"`
+}
+
+func (t *Service) GetBalance(ctx context.Context) (map[string]*Balance, error) {
+	var output struct {
+		BalancesMap [][2]json.RawMessage `json:"balancesMap"`
+	}
+	if err := t.cli(ctx, &output, "getbalance"); err != nil {
+		return nil, err
+	}
+	result := make(map[string]*Balance)
+	err := decodePairs(output.BalancesMap, func(key string) interface{} {
+		result[key] = &Balance{}
+		return result[key]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type TradingLimits struct {
+	MaxSell      uint64 `json:"maxSell"`
+	MaxBuy       uint64 `json:"maxBuy"`
+	ReservedSell uint64 `json:"reservedSell"`
+	ReservedBuy  uint64 `json:"reservedBuy"`
+}
+
+func (t *Service) GetTradingLimits(ctx context.Context) (map[string]*TradingLimits, error) {
+	var output struct {
+		LimitsMap [][2]json.RawMessage `json:"limitsMap"`
+	}
+	if err := t.cli(ctx, &output, "tradinglimits"); err != nil {
+		return nil, err
+	}
+	result := make(map[string]*TradingLimits)
+	err := decodePairs(output.LimitsMap, func(key string) interface{} {
+		result[key] = &TradingLimits{}
+		return result[key]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type Peer struct {
+	NodePubKey        string            `json:"nodePubKey"`
+	Alias             string            `json:"alias"`
+	Address           string            `json:"address"`
+	ConnextIdentifier string            `json:"connextIdentifier"`
+	LndPubKeys        map[string]string `json:"-"`
+}
+
+// ListPeers returns the connected opendex peers with their lnd pubkeys by
+// currency
+func (t *Service) ListPeers(ctx context.Context) ([]Peer, error) {
+	var output struct {
+		PeersList []struct {
+			Peer
+			LndPubKeysMap [][2]json.RawMessage `json:"lndPubKeysMap"`
+		} `json:"peersList"`
+	}
+	if err := t.cli(ctx, &output, "listpeers"); err != nil {
+		return nil, err
+	}
+	var result []Peer
+	for _, p := range output.PeersList {
+		peer := p.Peer
+		pubkeys := make(map[string]*string)
+		err := decodePairs(p.LndPubKeysMap, func(key string) interface{} {
+			pubkeys[key] = new(string)
+			return pubkeys[key]
+		})
+		if err != nil {
+			return nil, fmt.Errorf("peer %s: %w", peer.NodePubKey, err)
+		}
+		peer.LndPubKeys = make(map[string]string)
+		for currency, pubkey := range pubkeys {
+			peer.LndPubKeys[currency] = *pubkey
+		}
+		result = append(result, peer)
+	}
+	return result, nil
+}
+
+// OpenChannel opens a channel of amount (in coin units, e.g. "0.01") with
+// the opendex peer nodeIdentifier (pubkey or alias)
+func (t *Service) OpenChannel(ctx context.Context, currency string, amount string, nodeIdentifier string) (string, error) {
+	output, err := t.Exec(ctx, "opendex-cli", "openchannel", currency, amount, nodeIdentifier)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
@@ -11,7 +11,9 @@ type BaseConfig = base.Config
 type Config struct {
 	BaseConfig
 
-	PreserveConfig bool `usage:"Preserve opendexd.conf file during updates"`
+	PreserveConfig bool   `usage:"Preserve opendexd.conf file during updates"`
+	DisableTor     bool   `usage:"Disable tor and run %(name)s in clearnet-only mode"`
+	TorProxy       string `usage:"External tor SOCKS proxy (host:port) of %(name)s instead of the bundled tor"`
 }
 
 func (t *Service) GetDefaultConfig() interface{} {
@@ -30,5 +32,7 @@ func (t *Service) GetDefaultConfig() interface{} {
 			Dir:      filepath.Join(t.Context.GetDataDir(), t.Name),
 		},
 		PreserveConfig: false,
+		DisableTor:     false,
+		TorProxy:       "",
 	}
 }
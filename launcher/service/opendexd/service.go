@@ -7,17 +7,26 @@ import (
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker/launcher/service"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/tor"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type Base = base.Service
 
+// Pairs are the trading pairs opendexd supports with the lndbtc, lndltc and
+// connext (ETH) clients of this environment
+var Pairs = []string{"ETH/BTC", "LTC/BTC"}
+
 type Service struct {
 	*Base
 	RpcParams RpcParams
+	TorMode   tor.Mode
+	TorProxy  string
 }
 
 func New(ctx types.Context, name string) (*Service, error) {
@@ -81,6 +90,17 @@ func (t *Service) GetInfo(ctx context.Context) (*Info, error) {
 }
 
 func (t *Service) GetStatus(ctx context.Context) (string, error) {
+	status, err := t.getStatus(ctx)
+	if err != nil || status == "Ready" || strings.HasPrefix(status, "Container ") {
+		return status, err
+	}
+	if torStatus := t.getTorStatus(ctx); torStatus != "" {
+		return torStatus, nil
+	}
+	return status, nil
+}
+
+func (t *Service) getStatus(ctx context.Context) (string, error) {
 	status, err := t.Base.GetStatus(ctx)
 	if err != nil {
 		return "", err
@@ -139,6 +159,48 @@ func (t *Service) GetStatus(ctx context.Context) (string, error) {
 	return fmt.Sprintf("Waiting for %s", strings.Join(notReady, ", ")), nil
 }
 
+const (
+	WalletUnknown  = "unknown"
+	WalletMissing  = "missing"
+	WalletLocked   = "locked"
+	WalletUnlocked = "unlocked"
+)
+
+// GetWalletState tells whether the opendexd wallet is missing, locked or
+// unlocked, it is unknown while opendexd is not running
+func (t *Service) GetWalletState(ctx context.Context) string {
+	status, err := t.getStatus(ctx)
+	switch {
+	case err != nil:
+		return WalletUnknown
+	case strings.HasPrefix(status, "Wallet missing"):
+		return WalletMissing
+	case strings.HasPrefix(status, "Wallet locked"):
+		return WalletLocked
+	case status == "Ready" || strings.HasPrefix(status, "Waiting for"):
+		return WalletUnlocked
+	}
+	return WalletUnknown
+}
+
+// GetBackupFailure returns the failure of the backup process recorded by
+// opendexd-backup.sh and when it happened. The failure is empty while the
+// backup process runs.
+func (t *Service) GetBackupFailure() (string, time.Time, error) {
+	file := filepath.Join(t.DataDir, "backup.failed")
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return "", time.Time{}, nil
+	} else if err != nil {
+		return "", time.Time{}, err
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return strings.TrimSpace(string(data)), info.ModTime(), nil
+}
+
 func (t *Service) Apply(cfg interface{}) error {
 	c := cfg.(*Config)
 	if err := t.Base.Apply(c.BaseConfig); err != nil {
@@ -146,6 +208,30 @@ func (t *Service) Apply(cfg interface{}) error {
 	}
 	t.Environment["NODE_ENV"] = "production"
 
+	externalIp := t.Context.GetExternalIp()
+	if externalIp != "" {
+		t.Environment["EXTERNAL_IP"] = externalIp
+	}
+
+	torMode, err := tor.GetMode(c.DisableTor, c.TorProxy)
+	if err != nil {
+		return fmt.Errorf("%s: %w", t.Name, err)
+	}
+	t.TorMode = torMode
+	t.TorProxy = c.TorProxy
+	switch torMode {
+	case tor.Disabled:
+		t.Environment["TOR_DISABLED"] = "true"
+		if externalIp == "" {
+			t.Logger.Warnf("Tor is disabled and there is no external IP, %s is not reachable by peers", t.Name)
+		}
+	case tor.Proxy:
+		t.Environment["TOR_PROXY"] = c.TorProxy
+		if externalIp == "" {
+			t.Logger.Warnf("Tor is proxied and there is no external IP, %s is not reachable by peers", t.Name)
+		}
+	}
+
 	if c.PreserveConfig {
 		t.Environment["PRESERVE_CONFIG"] = "true"
 	} else {
@@ -174,13 +260,17 @@ func (t *Service) Apply(cfg interface{}) error {
 	switch network {
 	case types.Simnet:
 		port = 28886
-		t.Ports = append(t.Ports, "28885")
 	case types.Testnet:
 		port = 18886
-		t.Ports = append(t.Ports, "18885")
 	case types.Mainnet:
 		port = 8886
-		t.Ports = append(t.Ports, "8885")
+	}
+
+	if p2pPort := t.GetP2pPort(); externalIp != "" {
+		// peers connect to the advertised address on the host
+		t.Ports = append(t.Ports, fmt.Sprintf("%d:%d", p2pPort, p2pPort))
+	} else {
+		t.Ports = append(t.Ports, fmt.Sprintf("%d", p2pPort))
 	}
 
 	t.RpcParams.Type = "gRPC"
@@ -192,6 +282,18 @@ func (t *Service) Apply(cfg interface{}) error {
 	return nil
 }
 
+// GetP2pPort returns the port opendexd listens on for peers
+func (t *Service) GetP2pPort() uint16 {
+	switch t.Context.GetNetwork() {
+	case types.Simnet:
+		return 28885
+	case types.Testnet:
+		return 18885
+	default:
+		return 8885
+	}
+}
+
 type RpcParams struct {
 	Type    string `json:"type"`
 	Host    string `json:"host"`
@@ -0,0 +1,18 @@
+package opendexd
+
+import (
+	"context"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/tor"
+)
+
+func (t *Service) GetTorInfo() (*tor.Info, error) {
+	return tor.GetInfo(t.TorMode, t.TorProxy, t.DataDir)
+}
+
+func (t *Service) getTorStatus(ctx context.Context) string {
+	status, err := tor.GetStatus(ctx, t.TorMode, t)
+	if err != nil {
+		t.Logger.Debugf("Failed to check tor: %s", err)
+	}
+	return status
+}
@@ -0,0 +1,127 @@
+package opendexd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// sides and roles are printed as protobuf enum numbers
+var (
+	orderSides = []string{"buy", "sell"}
+	tradeRoles = []string{"taker", "maker", "internal"}
+)
+
+func enumName(names []string, i int) string {
+	if i < 0 || i >= len(names) {
+		return fmt.Sprintf("%d", i)
+	}
+	return names[i]
+}
+
+// Order quantities are in satoshis (1e-8) of the base currency
+type Order struct {
+	Id        string    `json:"id"`
+	PairId    string    `json:"pairId"`
+	Side      string    `json:"side"`
+	Price     float64   `json:"price"`
+	Quantity  uint64    `json:"quantity"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type rawOrder struct {
+	Id        string  `json:"id"`
+	PairId    string  `json:"pairId"`
+	Side      int     `json:"side"`
+	Price     float64 `json:"price"`
+	Quantity  uint64  `json:"quantity"`
+	CreatedAt int64   `json:"createdAt"`
+}
+
+func (t rawOrder) toOrder() Order {
+	return Order{
+		Id:        t.Id,
+		PairId:    t.PairId,
+		Side:      enumName(orderSides, t.Side),
+		Price:     t.Price,
+		Quantity:  t.Quantity,
+		CreatedAt: time.Unix(0, t.CreatedAt*int64(time.Millisecond)),
+	}
+}
+
+// ListOwnOrders returns the open orders of the node for the trading pair
+func (t *Service) ListOwnOrders(ctx context.Context, pair string) ([]Order, error) {
+	var output struct {
+		OrdersMap [][2]json.RawMessage `json:"ordersMap"`
+	}
+	if err := t.cli(ctx, &output, "listorders", pair, "Own"); err != nil {
+		return nil, err
+	}
+	type orders struct {
+		BuyOrdersList  []rawOrder `json:"buyOrdersList"`
+		SellOrdersList []rawOrder `json:"sellOrdersList"`
+	}
+	var lists []*orders
+	err := decodePairs(output.OrdersMap, func(key string) interface{} {
+		o := &orders{}
+		lists = append(lists, o)
+		return o
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := []Order{}
+	for _, l := range lists {
+		for _, o := range append(l.BuyOrdersList, l.SellOrdersList...) {
+			result = append(result, o.toOrder())
+		}
+	}
+	return result, nil
+}
+
+// Trade quantities are in satoshis (1e-8) of the base currency. Side is the
+// side of the local node.
+type Trade struct {
+	PairId     string    `json:"pairId"`
+	Side       string    `json:"side"`
+	Role       string    `json:"role"`
+	Price      float64   `json:"price"`
+	Quantity   uint64    `json:"quantity"`
+	ExecutedAt time.Time `json:"executedAt"`
+	RHash      string    `json:"rHash"`
+}
+
+// TradeHistory returns the latest limit (0 for all) trades of the node,
+// oldest first
+func (t *Service) TradeHistory(ctx context.Context, limit int) ([]Trade, error) {
+	var output struct {
+		TradesList []struct {
+			PairId     string  `json:"pairId"`
+			Side       int     `json:"side"`
+			Role       int     `json:"role"`
+			Price      float64 `json:"price"`
+			Quantity   uint64  `json:"quantity"`
+			ExecutedAt int64   `json:"executedAt"`
+			RHash      string  `json:"rHash"`
+		} `json:"tradesList"`
+	}
+	if err := t.cli(ctx, &output, "tradehistory", fmt.Sprintf("%d", limit)); err != nil {
+		return nil, err
+	}
+	result := []Trade{}
+	// opendex-cli lists the latest trade first
+	for i := len(output.TradesList) - 1; i >= 0; i-- {
+		trade := output.TradesList[i]
+		result = append(result, Trade{
+			PairId:     trade.PairId,
+			Side:       enumName(orderSides, trade.Side),
+			Role:       enumName(tradeRoles, trade.Role),
+			Price:      trade.Price,
+			Quantity:   trade.Quantity,
+			ExecutedAt: time.Unix(0, trade.ExecutedAt*int64(time.Millisecond)),
+			RHash:      trade.RHash,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRetries    = 3
+	DefaultRetryDelay = time.Second
+)
+
+// Client calls the REST API of the proxy
+type Client struct {
+	// BaseUrl is the proxy API URL, e.g. https://127.0.0.1:18889
+	BaseUrl string
+	Http    *http.Client
+
+	// Retries is how many times a request is retried while the proxy is
+	// unavailable. Requests which change state are only retried when they
+	// didn't reach the proxy.
+	Retries int
+	// RetryDelay is the delay of the first retry, it doubles on each retry
+	RetryDelay time.Duration
+}
+
+// New returns a client of the proxy at baseUrl. tlsConfig should pin the
+// proxy certificate and is nil when the proxy is served without TLS.
+func New(baseUrl string, tlsConfig *tls.Config) *Client {
+	return &Client{
+		BaseUrl: strings.TrimSuffix(baseUrl, "/"),
+		Http: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		},
+		Retries:    DefaultRetries,
+		RetryDelay: DefaultRetryDelay,
+	}
+}
+
+// isDialError tells whether err happened before the request was sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (t *Client) retryable(method string, err error) bool {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusServiceUnavailable {
+			// the request was not handled
+			return true
+		}
+		return method == http.MethodGet && errors.Is(apiErr, ErrUnavailable)
+	}
+	return method == http.MethodGet || isDialError(err)
+}
+
+func (t *Client) newRequest(ctx context.Context, method string, path string, query url.Values, body []byte) (*http.Request, error) {
+	u := t.BaseUrl + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+func decodeError(resp *http.Response) error {
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	e := ApiError{StatusCode: resp.StatusCode}
+	if err := json.Unmarshal(data, &e); err != nil || e.Message == "" {
+		e.Message = strings.TrimSpace(string(data))
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return &e
+}
+
+// send does the request with retries and returns the successful response
+func (t *Client) send(ctx context.Context, method string, path string, query url.Values, payload interface{}) (*http.Response, error) {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("encode payload: %w", err)
+		}
+	}
+
+	delay := t.RetryDelay
+	for attempt := 0; ; attempt++ {
+		req, err := t.newRequest(ctx, method, path, query, body)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		resp, err := t.Http.Do(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+		if err == nil {
+			err = decodeError(resp)
+			resp.Body.Close()
+		} else if ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else {
+			err = &RequestError{Method: method, Path: path, Err: err}
+		}
+
+		if attempt >= t.Retries || !t.retryable(method, err) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// do sends a request and decodes the JSON response into result unless it is
+// nil
+func (t *Client) do(ctx context.Context, method string, path string, payload interface{}, result interface{}) error {
+	resp, err := t.send(ctx, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,481 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c := New(server.URL, nil)
+	c.RetryDelay = time.Millisecond
+	return c
+}
+
+func writeJson(t *testing.T, w http.ResponseWriter, status int, body string) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write([]byte(body)); err != nil {
+		t.Errorf("write response: %s", err)
+	}
+}
+
+// expectRequest checks the method and path of the request and decodes its
+// JSON body into payload unless it is nil
+func expectRequest(t *testing.T, r *http.Request, method string, path string, payload interface{}) {
+	t.Helper()
+	if r.Method != method || r.URL.Path != path {
+		t.Errorf("got %s %s, want %s %s", r.Method, r.URL.Path, method, path)
+	}
+	if payload == nil {
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("got Content-Type %q, want application/json", ct)
+	}
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		t.Errorf("decode request: %s", err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		expectRequest(t, r, http.MethodPost, "/api/v1/opendexd/create", &payload)
+		if payload["password"] != "secret" {
+			t.Errorf("got password %q", payload["password"])
+		}
+		writeJson(t, w, http.StatusOK, `{"seedMnemonic":["a","b"],"initializedLnds":["BTC","LTC"],"initializedConnext":true}`)
+	})
+
+	resp, err := c.Create(context.Background(), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CreateResponse{SeedMnemonic: []string{"a", "b"}, InitializedLnds: []string{"BTC", "LTC"}, InitializedConnext: true}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("got %+v, want %+v", resp, want)
+	}
+}
+
+func TestUnlock(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		expectRequest(t, r, http.MethodPost, "/api/v1/opendexd/unlock", &payload)
+		if payload["password"] != "secret" {
+			t.Errorf("got password %q", payload["password"])
+		}
+		writeJson(t, w, http.StatusOK, `{"unlockedLnds":["BTC"],"lockedLnds":["LTC"],"unlockedConnext":true}`)
+	})
+
+	resp, err := c.Unlock(context.Background(), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &UnlockResponse{UnlockedLnds: []string{"BTC"}, LockedLnds: []string{"LTC"}, UnlockedConnext: true}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("got %+v, want %+v", resp, want)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	req := RestoreRequest{
+		Password:         "secret",
+		SeedMnemonic:     []string{"a", "b"},
+		LndBackups:       map[string][]byte{"BTC": []byte("backup")},
+		OpendexdDatabase: []byte("db"),
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload RestoreRequest
+		expectRequest(t, r, http.MethodPost, "/api/v1/opendexd/restore", &payload)
+		if !reflect.DeepEqual(payload, req) {
+			t.Errorf("got %+v, want %+v", payload, req)
+		}
+		writeJson(t, w, http.StatusOK, `{"restoredLnds":["BTC"],"restoredConnext":false}`)
+	})
+
+	resp, err := c.Restore(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &RestoreResponse{RestoredLnds: []string{"BTC"}}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("got %+v, want %+v", resp, want)
+	}
+}
+
+func TestGetInfo(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expectRequest(t, r, http.MethodGet, "/api/v1/opendexd/getinfo", nil)
+		writeJson(t, w, http.StatusOK, `{
+			"version": "1.0.0",
+			"nodePubKey": "02ab",
+			"alias": "node",
+			"uris": ["02ab@1.2.3.4:8885"],
+			"network": "testnet",
+			"numPeers": 3,
+			"numPairs": 2,
+			"orders": {"peer": 5, "own": 1},
+			"lnd": {"BTC": {"status": "Ready", "chains": [{"chain": "bitcoin", "network": "testnet"}], "blockheight": 100, "uris": ["03cd@1.2.3.4:9735"], "version": "0.11", "alias": "lnd"}},
+			"connext": {"status": "Ready", "address": "0x1", "version": "7"}
+		}`)
+	})
+
+	info, err := c.GetInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Info{
+		Version:    "1.0.0",
+		NodePubKey: "02ab",
+		Alias:      "node",
+		Uris:       []string{"02ab@1.2.3.4:8885"},
+		Network:    "testnet",
+		NumPeers:   3,
+		NumPairs:   2,
+		Orders:     Orders{Peer: 5, Own: 1},
+		Lnd: map[string]LndInfo{
+			"BTC": {
+				Status:      "Ready",
+				Chains:      []Chain{{Chain: "bitcoin", Network: "testnet"}},
+				Blockheight: 100,
+				Uris:        []string{"03cd@1.2.3.4:9735"},
+				Version:     "0.11",
+				Alias:       "lnd",
+			},
+		},
+		Connext: ConnextInfo{Status: "Ready", Address: "0x1", Version: "7"},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("got %+v, want %+v", info, want)
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	tests := []struct {
+		currency string
+		path     string
+	}{
+		{"", "/api/v1/opendexd/getbalance"},
+		{"BTC", "/api/v1/opendexd/getbalance/BTC"},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			expectRequest(t, r, http.MethodGet, tt.path, nil)
+			writeJson(t, w, http.StatusOK, `{"balances":{"BTC":{
+				"totalBalance": "18446744073709551615",
+				"channelBalance": "1",
+				"pendingChannelBalance": "2",
+				"inactiveChannelBalance": "3",
+				"walletBalance": "4",
+				"unconfirmedWalletBalance": "5"
+			}}}`)
+		})
+
+		balances, err := c.GetBalance(context.Background(), tt.currency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]*Balance{
+			"BTC": {
+				TotalBalance:             18446744073709551615,
+				ChannelBalance:           1,
+				PendingChannelBalance:    2,
+				InactiveChannelBalance:   3,
+				WalletBalance:            4,
+				UnconfirmedWalletBalance: 5,
+			},
+		}
+		if !reflect.DeepEqual(balances, want) {
+			t.Errorf("currency %q: got %+v, want %+v", tt.currency, balances["BTC"], want["BTC"])
+		}
+	}
+}
+
+func TestListStatus(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expectRequest(t, r, http.MethodGet, "/api/v1/status", nil)
+		writeJson(t, w, http.StatusOK, `[{"service":"lndbtc","status":"Ready"},{"service":"opendexd","status":"Waiting for lndbtc"}]`)
+	})
+
+	statuses, err := c.ListStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ServiceStatus{{Service: "lndbtc", Status: "Ready"}, {Service: "opendexd", Status: "Waiting for lndbtc"}}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("got %+v, want %+v", statuses, want)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expectRequest(t, r, http.MethodGet, "/api/v1/status/lndbtc", nil)
+		writeJson(t, w, http.StatusOK, `{"service":"lndbtc","status":"Ready"}`)
+	})
+
+	status, err := c.GetStatus(context.Background(), "lndbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "Ready" {
+		t.Errorf("got status %q", status)
+	}
+}
+
+func TestGetLogs(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expectRequest(t, r, http.MethodGet, "/api/v1/logs/opendexd", nil)
+		if since, tail := r.URL.Query().Get("since"), r.URL.Query().Get("tail"); since != "1h" || tail != "10" {
+			t.Errorf("got since=%q tail=%q", since, tail)
+		}
+		_, _ = w.Write([]byte("line 1\nline 2\n"))
+	})
+
+	logs, err := c.GetLogs(context.Background(), "opendexd", LogsOptions{Since: "1h", Tail: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+	data, err := ioutil.ReadAll(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "line 1\nline 2\n" {
+		t.Errorf("got logs %q", data)
+	}
+}
+
+func TestApiError(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		message string
+		target  error
+	}{
+		{http.StatusUnauthorized, `{"message":"invalid token"}`, "invalid token", ErrUnauthorized},
+		{http.StatusForbidden, `{"message":"not allowed"}`, "not allowed", ErrUnauthorized},
+		{http.StatusNotFound, `{"message":"no such service"}`, "no such service", ErrNotFound},
+		{http.StatusBadGateway, `bad gateway`, "bad gateway", ErrUnavailable},
+		{http.StatusServiceUnavailable, ``, "Service Unavailable", ErrUnavailable},
+		{http.StatusGatewayTimeout, `{"message":"timeout"}`, "timeout", ErrUnavailable},
+		{http.StatusBadRequest, `{"message":"bad password"}`, "bad password", nil},
+	}
+	targets := []error{ErrUnauthorized, ErrNotFound, ErrUnavailable}
+
+	for _, tt := range tests {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			writeJson(t, w, tt.status, tt.body)
+		})
+		c.Retries = 0
+
+		_, err := c.GetStatus(context.Background(), "opendexd")
+		var apiErr *ApiError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("status %d: got %v, want an ApiError", tt.status, err)
+		}
+		if apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+			t.Errorf("status %d: got %d %q, want message %q", tt.status, apiErr.StatusCode, apiErr.Message, tt.message)
+		}
+		for _, target := range targets {
+			if got, want := errors.Is(err, target), target == tt.target; got != want {
+				t.Errorf("status %d: errors.Is(%v) = %t", tt.status, target, got)
+			}
+		}
+	}
+}
+
+func TestRequestErrorIsUnavailable(t *testing.T) {
+	err := error(&RequestError{Method: http.MethodGet, Path: "/api/v1/status", Err: errors.New("connection reset")})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("%v is not ErrUnavailable", err)
+	}
+}
+
+func TestRetryServiceUnavailable(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		var calls int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				writeJson(t, w, http.StatusServiceUnavailable, `{"message":"starting"}`)
+				return
+			}
+			writeJson(t, w, http.StatusOK, `{}`)
+		})
+
+		if err := c.do(context.Background(), method, "/api/v1/test", nil, nil); err != nil {
+			t.Fatalf("%s: %s", method, err)
+		}
+		if calls := atomic.LoadInt32(&calls); calls != 3 {
+			t.Errorf("%s: got %d calls, want 3", method, calls)
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeJson(t, w, http.StatusServiceUnavailable, `{"message":"starting"}`)
+	})
+
+	err := c.do(context.Background(), http.MethodGet, "/api/v1/test", nil, nil)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("got %v, want ErrUnavailable", err)
+	}
+	if want := int32(DefaultRetries + 1); atomic.LoadInt32(&calls) != want {
+		t.Errorf("got %d calls, want %d", atomic.LoadInt32(&calls), want)
+	}
+}
+
+func TestRetryBadGatewayOnlyGet(t *testing.T) {
+	tests := []struct {
+		method string
+		calls  int32
+	}{
+		{http.MethodGet, 2},
+		{http.MethodPost, 1},
+	}
+	for _, tt := range tests {
+		var calls int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				writeJson(t, w, http.StatusBadGateway, `{"message":"opendexd is down"}`)
+				return
+			}
+			writeJson(t, w, http.StatusOK, `{}`)
+		})
+
+		err := c.do(context.Background(), tt.method, "/api/v1/test", nil, nil)
+		if tt.method == http.MethodPost && !errors.Is(err, ErrUnavailable) {
+			t.Errorf("POST: got %v, want ErrUnavailable", err)
+		}
+		if calls := atomic.LoadInt32(&calls); calls != tt.calls {
+			t.Errorf("%s: got %d calls, want %d", tt.method, calls, tt.calls)
+		}
+	}
+}
+
+// failingDials makes the first n dials of the client fail like a proxy which
+// doesn't listen yet
+func failingDials(c *Client, n int32) *int32 {
+	var dials int32
+	d := net.Dialer{}
+	c.Http.Transport.(*http.Transport).DialContext = func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		if atomic.AddInt32(&dials, 1) <= n {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+		}
+		return d.DialContext(ctx, network, addr)
+	}
+	return &dials
+}
+
+func TestRetryDialError(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		var calls int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			writeJson(t, w, http.StatusOK, `{}`)
+		})
+		dials := failingDials(c, 2)
+
+		if err := c.do(context.Background(), method, "/api/v1/test", nil, nil); err != nil {
+			t.Fatalf("%s: %s", method, err)
+		}
+		if dials, calls := atomic.LoadInt32(dials), atomic.LoadInt32(&calls); dials != 3 || calls != 1 {
+			t.Errorf("%s: got %d dials and %d calls, want 3 and 1", method, dials, calls)
+		}
+	}
+}
+
+func TestDialErrorGivesUp(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	})
+	dials := failingDials(c, 100)
+
+	err := c.do(context.Background(), http.MethodPost, "/api/v1/test", nil, nil)
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || !errors.Is(err, ErrUnavailable) {
+		t.Errorf("got %v, want an unavailable RequestError", err)
+	}
+	if want := int32(DefaultRetries + 1); atomic.LoadInt32(dials) != want {
+		t.Errorf("got %d dials, want %d", atomic.LoadInt32(dials), want)
+	}
+}
+
+func TestNoRetryOfReceivedPost(t *testing.T) {
+	tests := []struct {
+		method string
+		calls  int32
+	}{
+		{http.MethodGet, 2},
+		{http.MethodPost, 1},
+	}
+	for _, tt := range tests {
+		var calls int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				// the proxy dies while handling the request
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err != nil {
+					t.Errorf("hijack: %s", err)
+					return
+				}
+				_ = conn.Close()
+				return
+			}
+			writeJson(t, w, http.StatusOK, `{}`)
+		})
+
+		err := c.do(context.Background(), tt.method, "/api/v1/test", map[string]string{"password": "secret"}, nil)
+		if tt.method == http.MethodPost {
+			var reqErr *RequestError
+			if !errors.As(err, &reqErr) {
+				t.Errorf("POST: got %v, want a RequestError", err)
+			}
+		} else if err != nil {
+			t.Errorf("GET: %s", err)
+		}
+		if calls := atomic.LoadInt32(&calls); calls != tt.calls {
+			t.Errorf("%s: got %d calls, want %d", tt.method, calls, tt.calls)
+		}
+	}
+}
+
+func TestCancelDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJson(t, w, http.StatusServiceUnavailable, `{"message":"starting"}`)
+		// the client is backing off after this response
+		cancel()
+	})
+	c.RetryDelay = time.Hour
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.do(ctx, http.MethodGet, "/api/v1/test", nil, nil)
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the backoff was not interrupted")
+	}
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	// ErrUnavailable is a proxy or service which cannot serve the request yet
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// ApiError is an error response of the proxy
+type ApiError struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+}
+
+func (t *ApiError) Error() string {
+	return fmt.Sprintf("[http %d] %s", t.StatusCode, t.Message)
+}
+
+func (t *ApiError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return t.StatusCode == http.StatusUnauthorized || t.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return t.StatusCode == http.StatusNotFound
+	case ErrUnavailable:
+		return t.StatusCode == http.StatusBadGateway || t.StatusCode == http.StatusServiceUnavailable || t.StatusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
+
+// RequestError is a request which didn't get a response from the proxy
+type RequestError struct {
+	Method string
+	Path   string
+	Err    error
+}
+
+func (t *RequestError) Error() string {
+	return fmt.Sprintf("%s %s: %s", t.Method, t.Path, t.Err)
+}
+
+func (t *RequestError) Unwrap() error {
+	return t.Err
+}
+
+func (t *RequestError) Is(target error) bool {
+	return target == ErrUnavailable
+}
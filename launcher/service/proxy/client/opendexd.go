@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type CreateResponse struct {
+	SeedMnemonic       []string `json:"seedMnemonic"`
+	InitializedLnds    []string `json:"initializedLnds"`
+	InitializedConnext bool     `json:"initializedConnext"`
+}
+
+// Create creates the opendexd node and the wallets of its lnds and connext
+func (t *Client) Create(ctx context.Context, password string) (*CreateResponse, error) {
+	var result CreateResponse
+	payload := map[string]interface{}{
+		"password": password,
+	}
+	if err := t.do(ctx, http.MethodPost, "/api/v1/opendexd/create", payload, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type UnlockResponse struct {
+	UnlockedLnds    []string `json:"unlockedLnds"`
+	LockedLnds      []string `json:"lockedLnds"`
+	UnlockedConnext bool     `json:"unlockedConnext"`
+}
+
+// Unlock unlocks opendexd and the wallets of its lnds
+func (t *Client) Unlock(ctx context.Context, password string) (*UnlockResponse, error) {
+	var result UnlockResponse
+	payload := map[string]interface{}{
+		"password": password,
+	}
+	if err := t.do(ctx, http.MethodPost, "/api/v1/opendexd/unlock", payload, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type RestoreRequest struct {
+	Password     string   `json:"password"`
+	SeedMnemonic []string `json:"seedMnemonic"`
+	// LndBackups are the channel backups by currency
+	LndBackups       map[string][]byte `json:"lndBackups,omitempty"`
+	OpendexdDatabase []byte            `json:"opendexdDatabase,omitempty"`
+}
+
+type RestoreResponse struct {
+	RestoredLnds    []string `json:"restoredLnds"`
+	RestoredConnext bool     `json:"restoredConnext"`
+}
+
+// Restore restores the opendexd node and its wallets from the seed mnemonic
+// and the backups
+func (t *Client) Restore(ctx context.Context, req RestoreRequest) (*RestoreResponse, error) {
+	var result RestoreResponse
+	if err := t.do(ctx, http.MethodPost, "/api/v1/opendexd/restore", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type LndInfo struct {
+	Status      string   `json:"status"`
+	Chains      []Chain  `json:"chains"`
+	Blockheight uint32   `json:"blockheight"`
+	Uris        []string `json:"uris"`
+	Version     string   `json:"version"`
+	Alias       string   `json:"alias"`
+}
+
+type Chain struct {
+	Chain   string `json:"chain"`
+	Network string `json:"network"`
+}
+
+type ConnextInfo struct {
+	Status  string `json:"status"`
+	Address string `json:"address"`
+	Version string `json:"version"`
+}
+
+type Orders struct {
+	Peer uint32 `json:"peer"`
+	Own  uint32 `json:"own"`
+}
+
+type Info struct {
+	Version    string             `json:"version"`
+	NodePubKey string             `json:"nodePubKey"`
+	Alias      string             `json:"alias"`
+	Uris       []string           `json:"uris"`
+	Network    string             `json:"network"`
+	NumPeers   uint32             `json:"numPeers"`
+	NumPairs   uint32             `json:"numPairs"`
+	Orders     Orders             `json:"orders"`
+	Lnd        map[string]LndInfo `json:"lnd"`
+	Connext    ConnextInfo        `json:"connext"`
+}
+
+// GetInfo returns the opendexd node info
+func (t *Client) GetInfo(ctx context.Context) (*Info, error) {
+	var result Info
+	if err := t.do(ctx, http.MethodGet, "/api/v1/opendexd/getinfo", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Balance amounts are in satoshis, the proxy encodes them as strings
+type Balance struct {
+	TotalBalance             uint64 `json:"totalBalance,string"`
+	ChannelBalance           uint64 `json:"channelBalance,string"`
+	PendingChannelBalance    uint64 `json:"pendingChannelBalance,string"`
+	InactiveChannelBalance   uint64 `json:"inactiveChannelBalance,string"`
+	WalletBalance            uint64 `json:"walletBalance,string"`
+	UnconfirmedWalletBalance uint64 `json:"unconfirmedWalletBalance,string"`
+}
+
+// GetBalance returns the balances by currency, only of currency if it is
+// given
+func (t *Client) GetBalance(ctx context.Context, currency string) (map[string]*Balance, error) {
+	var result struct {
+		Balances map[string]*Balance `json:"balances"`
+	}
+	path := "/api/v1/opendexd/getbalance"
+	if currency != "" {
+		path = fmt.Sprintf("%s/%s", path, url.PathEscape(currency))
+	}
+	if err := t.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Balances, nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type ServiceStatus struct {
+	Service string `json:"service"`
+	Status  string `json:"status"`
+}
+
+// ListStatus returns the status of all services
+func (t *Client) ListStatus(ctx context.Context) ([]ServiceStatus, error) {
+	var result []ServiceStatus
+	if err := t.do(ctx, http.MethodGet, "/api/v1/status", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetStatus returns the status of service
+func (t *Client) GetStatus(ctx context.Context, service string) (string, error) {
+	var result ServiceStatus
+	if err := t.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/status/%s", url.PathEscape(service)), nil, &result); err != nil {
+		return "", err
+	}
+	return result.Status, nil
+}
+
+type LogsOptions struct {
+	// Since is a timestamp or a relative time like "1h"
+	Since string
+	// Tail is the number of lines from the end, all lines when it is zero
+	Tail int
+}
+
+// GetLogs returns the logs of service. The caller closes the reader.
+func (t *Client) GetLogs(ctx context.Context, service string, opts LogsOptions) (io.ReadCloser, error) {
+	query := url.Values{}
+	if opts.Since != "" {
+		query.Set("since", opts.Since)
+	}
+	if opts.Tail > 0 {
+		query.Set("tail", strconv.Itoa(opts.Tail))
+	}
+	resp, err := t.send(ctx, http.MethodGet, fmt.Sprintf("/api/v1/logs/%s", url.PathEscape(service)), query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
@@ -11,7 +11,9 @@ type BaseConfig = base.Config
 type Config struct {
 	BaseConfig
 
-	Tls bool `usage:"Enabled TLS support"`
+	Tls     bool   `usage:"Enabled TLS support"`
+	TlsCert string `usage:"TLS certificate file of the API (default: a generated self-signed one)"`
+	TlsKey  string `usage:"TLS key file of the TLS certificate"`
 }
 
 func (t *Service) GetDefaultConfig() interface{} {
@@ -6,6 +6,7 @@ import (
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
 	"net"
+	"path/filepath"
 	"runtime"
 	"time"
 )
@@ -86,18 +87,48 @@ func (t *Service) Apply(cfg interface{}) error {
 		t.Volumes = append(t.Volumes, "/var/run/docker.sock:/var/run/docker.sock")
 	}
 
+	if err := validateTls(c); err != nil {
+		return fmt.Errorf("%s: %w", t.Name, err)
+	}
+
 	if c.Tls {
+		if err := t.ensureTlsCert(c); err != nil {
+			return fmt.Errorf("%s: %w", t.Name, err)
+		}
 		t.Command = append(t.Command, "--tls")
 		t.RpcParams.Scheme = "https"
 	} else {
 		t.RpcParams.Scheme = "http"
 	}
 
-	t.Ports = append(t.Ports, fmt.Sprintf("127.0.0.1:%d:8080", t.RpcParams.Port))
+	t.Ports = append(t.Ports, fmt.Sprintf("127.0.0.1:%d:%d", t.RpcParams.Port, ApiPort))
 
 	return nil
 }
 
+const (
+	// TlsCertFile is the TLS certificate of the proxy API in its data dir
+	TlsCertFile = "tls.crt"
+	// ApiPort is the port of the proxy API inside the compose network
+	ApiPort = 8080
+)
+
+// GetTlsCertPath returns the TLS certificate path of the proxy API on the host
+func (t *Service) GetTlsCertPath() string {
+	return filepath.Join(t.DataDir, TlsCertFile)
+}
+
+// GetPreviousTlsCertPath returns the path of the rotated certificate which a
+// running proxy may still serve
+func (t *Service) GetPreviousTlsCertPath() string {
+	return filepath.Join(t.DataDir, previousTlsCertFile)
+}
+
+// GetInternalUri returns the proxy API URI for other containers
+func (t *Service) GetInternalUri() string {
+	return fmt.Sprintf("%s://%s:%d", t.RpcParams.Scheme, t.Name, ApiPort)
+}
+
 type RpcParams struct {
 	Type   string `json:"type"`
 	Scheme string `json:"scheme"`
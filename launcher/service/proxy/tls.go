@@ -0,0 +1,258 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker/launcher/utils"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// TlsKeyFile is the TLS key of the proxy API in its data dir
+	TlsKeyFile = "tls.key"
+	// previousTlsCertFile keeps the rotated certificate which a running proxy
+	// may still serve until it is restarted
+	previousTlsCertFile = "tls.crt.old"
+
+	CertValidity = 365 * 24 * time.Hour
+	// CertRotateBefore is how long before the expiry a generated certificate
+	// is replaced
+	CertRotateBefore = 30 * 24 * time.Hour
+)
+
+func readCert(path string) (*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate in %s", path)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// generateCert creates a self-signed certificate for the local and compose
+// network names of the proxy
+func (t *Service) generateCert(certFile string, keyFile string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"opendex-docker"}, CommonName: t.Name},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(CertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost", t.Name},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteFileSecure(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+// copyIfChanged copies src into dst unless dst has the same content
+func copyIfChanged(src string, dst string, perm os.FileMode) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if current, err := ioutil.ReadFile(dst); err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	if err := ioutil.WriteFile(dst, data, perm); err != nil {
+		return err
+	}
+	return os.Chmod(dst, perm)
+}
+
+// keepPreviousCert saves the certificate about to be replaced, so that it
+// stays pinned until the proxy restarts with the new one
+func keepPreviousCert(certFile string) error {
+	data, err := ioutil.ReadFile(certFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(filepath.Dir(certFile), previousTlsCertFile), data, 0644)
+}
+
+// validateTls checks the TLS options and the user supplied certificate
+func validateTls(c *Config) error {
+	if !c.Tls {
+		if c.TlsCert != "" || c.TlsKey != "" {
+			return errors.New("a TLS certificate is given but TLS is disabled")
+		}
+		return nil
+	}
+	if (c.TlsCert == "") != (c.TlsKey == "") {
+		return errors.New("both the TLS certificate and key are required")
+	}
+	if c.TlsCert == "" {
+		return nil
+	}
+	if _, err := tls.LoadX509KeyPair(c.TlsCert, c.TlsKey); err != nil {
+		return fmt.Errorf("invalid TLS certificate or key: %w", err)
+	}
+	cert, err := readCert(c.TlsCert)
+	if err != nil {
+		return err
+	}
+	if time.Now().After(cert.NotAfter) {
+		return fmt.Errorf("the TLS certificate %s expired at %s", c.TlsCert, cert.NotAfter.Format(time.RFC3339))
+	}
+	if len(cert.DNSNames) == 0 && len(cert.IPAddresses) == 0 {
+		return fmt.Errorf("the TLS certificate %s has no subject alternative names", c.TlsCert)
+	}
+	return nil
+}
+
+// ensureTlsCert puts the user supplied certificate into the proxy data dir,
+// or else generates a certificate when there is none or it expires soon
+func (t *Service) ensureTlsCert(c *Config) error {
+	certFile := t.GetTlsCertPath()
+	keyFile := filepath.Join(t.DataDir, TlsKeyFile)
+
+	if err := os.MkdirAll(t.DataDir, 0755); err != nil {
+		return err
+	}
+
+	if c.TlsCert != "" {
+		if cert, err := readCert(certFile); err == nil {
+			if userCert, err := readCert(c.TlsCert); err == nil && !cert.Equal(userCert) {
+				if err := keepPreviousCert(certFile); err != nil {
+					return err
+				}
+			}
+		}
+		if err := copyIfChanged(c.TlsCert, certFile, 0644); err != nil {
+			return fmt.Errorf("copy TLS certificate: %w", err)
+		}
+		if err := copyIfChanged(c.TlsKey, keyFile, 0600); err != nil {
+			return fmt.Errorf("copy TLS key: %w", err)
+		}
+		if cert, err := readCert(certFile); err == nil && time.Until(cert.NotAfter) < CertRotateBefore {
+			t.Logger.Warnf("The TLS certificate %s expires at %s, replace it soon", c.TlsCert, cert.NotAfter.Format(time.RFC3339))
+		}
+		return nil
+	}
+
+	cert, err := readCert(certFile)
+	if err == nil && utils.FileExists(keyFile) && time.Until(cert.NotAfter) > CertRotateBefore {
+		return nil
+	}
+	if err == nil {
+		t.Logger.Infof("Rotating the TLS certificate which expires at %s", cert.NotAfter.Format(time.RFC3339))
+	} else if !os.IsNotExist(err) {
+		t.Logger.Warnf("Replacing the invalid TLS certificate: %s", err)
+	}
+	if err := keepPreviousCert(certFile); err != nil {
+		return err
+	}
+	if err := t.generateCert(certFile, keyFile); err != nil {
+		return fmt.Errorf("generate TLS certificate: %w", err)
+	}
+	return nil
+}
+
+// ServesCurrentCert tells whether the running proxy serves the certificate
+// in its data dir. The proxy only loads the certificate when it starts, so it
+// still serves the old one after a rotation until it is restarted.
+func (t *Service) ServesCurrentCert(ctx context.Context) (bool, error) {
+	if t.RpcParams.Scheme != "https" {
+		return true, nil
+	}
+	cert, err := readCert(t.GetTlsCertPath())
+	if err != nil {
+		return false, err
+	}
+	dialer := tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 3 * time.Second},
+		// only the served certificate is compared, nothing is sent
+		Config: &tls.Config{InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("127.0.0.1:%d", t.RpcParams.Port))
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	served := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	return len(served) > 0 && served[0].Equal(cert), nil
+}
+
+// GetTlsConfig returns the client TLS configuration trusting only the proxy
+// certificate and the previous one a running proxy may still serve
+func (t *Service) GetTlsConfig() (*tls.Config, error) {
+	cert, err := readCert(t.GetTlsCertPath())
+	if err != nil {
+		return nil, fmt.Errorf("read proxy TLS certificate: %w", err)
+	}
+	pins := []*x509.Certificate{cert}
+	if previous, err := readCert(filepath.Join(t.DataDir, previousTlsCertFile)); err == nil && time.Now().Before(previous.NotAfter) {
+		pins = append(pins, previous)
+	}
+
+	pool := x509.NewCertPool()
+	for _, pin := range pins {
+		pool.AddCert(pin)
+	}
+
+	// the proxy is reached through 127.0.0.1 which a user supplied
+	// certificate may not name
+	serverName := "localhost"
+	if len(cert.DNSNames) > 0 {
+		serverName = cert.DNSNames[0]
+	} else if len(cert.IPAddresses) > 0 {
+		serverName = cert.IPAddresses[0].String()
+	}
+
+	return &tls.Config{
+		RootCAs:    pool,
+		ServerName: serverName,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("no proxy certificate")
+			}
+			for _, pin := range pins {
+				if state.PeerCertificates[0].Equal(pin) {
+					return nil
+				}
+			}
+			return errors.New("the proxy certificate doesn't match the pinned one")
+		},
+	}, nil
+}
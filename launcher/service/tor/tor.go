@@ -0,0 +1,123 @@
+package tor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type Mode string
+
+const (
+	// Bundled runs tor in the service container with a hidden service
+	Bundled Mode = "bundled"
+	// Proxy connects to peers through an external tor SOCKS proxy
+	Proxy Mode = "proxy"
+	// Disabled runs the service in clearnet-only mode
+	Disabled Mode = "disabled"
+)
+
+type Info struct {
+	Mode         Mode   `json:"mode"`
+	Proxy        string `json:"proxy,omitempty"`
+	OnionAddress string `json:"onionAddress,omitempty"`
+}
+
+// GetMode validates the tor options of a service
+func GetMode(disable bool, proxy string) (Mode, error) {
+	if disable && proxy != "" {
+		return "", fmt.Errorf("tor can't be both disabled and proxied")
+	}
+	if disable {
+		return Disabled, nil
+	}
+	if proxy != "" {
+		if _, _, err := net.SplitHostPort(proxy); err != nil {
+			return "", fmt.Errorf("invalid tor proxy %q (expected host:port)", proxy)
+		}
+		return Proxy, nil
+	}
+	return Bundled, nil
+}
+
+// LogLines is how many latest log lines are searched for tor problems
+const LogLines = "300"
+
+// GetInfo describes the tor setup of a service whose bundled tor keeps its
+// hidden service in <dataDir>/tor
+func GetInfo(mode Mode, proxy string, dataDir string) (*Info, error) {
+	info := Info{
+		Mode:  mode,
+		Proxy: proxy,
+	}
+	if mode == Bundled {
+		addr, err := ReadOnionAddress(dataDir)
+		if err != nil {
+			return nil, fmt.Errorf("read onion address: %w", err)
+		}
+		info.OnionAddress = addr
+	}
+	return &info, nil
+}
+
+// ReadOnionAddress reads the hidden service hostname tor writes into
+// <dataDir>/tor/hostname. It returns an empty string if there is none yet.
+func ReadOnionAddress(dataDir string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dataDir, "tor", "hostname"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+var (
+	reBootstrapped = regexp.MustCompile(`\[notice\] Bootstrapped \d+%`)
+	reProblem      = regexp.MustCompile(`\[warn\] Problem bootstrapping\. (Stuck at \d+%[^.(]*)`)
+	reError        = regexp.MustCompile(`\[err\] (.*)$`)
+)
+
+// CheckBootstrap looks for tor bootstrapping problems in the latest log
+// lines of a container. It returns an empty string when tor bootstrapped or
+// is still making progress.
+func CheckBootstrap(lines []string) string {
+	problem := ""
+	for _, line := range lines {
+		if reBootstrapped.MatchString(line) {
+			// progress clears earlier problems
+			problem = ""
+		} else if m := reProblem.FindStringSubmatch(line); m != nil {
+			problem = strings.TrimSpace(m[1])
+		} else if m := reError.FindStringSubmatch(line); m != nil {
+			problem = strings.TrimSpace(m[1])
+		}
+	}
+	return problem
+}
+
+type logSource interface {
+	GetLogs(ctx context.Context, since string, tail string) ([]string, error)
+}
+
+// GetStatus reports the bundled tor of a service failing to bootstrap, which
+// leaves the service without peers. It returns an empty string otherwise.
+func GetStatus(ctx context.Context, mode Mode, s logSource) (string, error) {
+	if mode != Bundled {
+		return "", nil
+	}
+	lines, err := s.GetLogs(ctx, "", LogLines)
+	if err != nil {
+		return "", fmt.Errorf("get logs: %w", err)
+	}
+	if problem := CheckBootstrap(lines); problem != "" {
+		return fmt.Sprintf("Tor failed to bootstrap (%s)", problem), nil
+	}
+	return "", nil
+}
@@ -10,6 +10,8 @@ type BaseConfig = base.Config
 
 type Config struct {
 	BaseConfig
+
+	Port uint16 `usage:"Local port of the webui"`
 }
 
 func (t *Service) GetDefaultConfig() interface{} {
@@ -21,11 +23,22 @@ func (t *Service) GetDefaultConfig() interface{} {
 		image = "opendexnetwork/webui:latest"
 	}
 
+	var port uint16
+	switch network {
+	case types.Simnet:
+		port = 28888
+	case types.Testnet:
+		port = 18888
+	case types.Mainnet:
+		port = 8888
+	}
+
 	return &Config{
 		BaseConfig: BaseConfig{
 			Image:    t.Base.GetBranchImage(image),
 			Disabled: true,
 			Dir:      filepath.Join(t.Context.GetDataDir(), t.Name),
 		},
+		Port: port,
 	}
 }
@@ -1,14 +1,33 @@
 package webui
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/opendexnetwork/opendex-docker/launcher/service/base"
+	"github.com/opendexnetwork/opendex-docker/launcher/service/proxy"
 	"github.com/opendexnetwork/opendex-docker/launcher/types"
+	"github.com/opendexnetwork/opendex-docker/launcher/utils"
+	"net"
+	"net/url"
+	"path/filepath"
+	"time"
 )
 
 type Base = base.Service
 
+// containerPort is the port of the gateway in the webui container. It checks
+// the access token and forwards to the proxy API and the webui server.
+const containerPort = 8081
+
 type Service struct {
 	*Base
+
+	Port uint16
+	// Token is the access token of the webui
+	Token string
+	// files are the host files mounted into the container
+	files []string
 }
 
 func New(ctx types.Context, name string) (*Service, error) {
@@ -22,10 +41,118 @@ func New(ctx types.Context, name string) (*Service, error) {
 	}, nil
 }
 
+func (t *Service) getProxy() (*proxy.Service, error) {
+	s, err := t.Context.GetService("proxy")
+	if err != nil {
+		return nil, err
+	}
+	p, ok := s.(*proxy.Service)
+	if !ok {
+		return nil, errors.New("cannot convert to *proxy.Service")
+	}
+	return p, nil
+}
+
+// getToken returns the access token kept in the secret store so that
+// bookmarked webui URLs keep working across "gen" runs
+func (t *Service) getToken() (string, error) {
+	return t.LoadOrCreateSecret("token", 32)
+}
+
 func (t *Service) Apply(cfg interface{}) error {
 	c := cfg.(*Config)
 	if err := t.Base.Apply(c.BaseConfig); err != nil {
 		return err
 	}
+
+	t.Port = c.Port
+
+	if t.Disabled {
+		return nil
+	}
+
+	p, err := t.getProxy()
+	if err != nil {
+		return err
+	}
+	if p.IsDisabled() {
+		return fmt.Errorf("%s needs the proxy, enable it with --proxy.disabled=false", t.Name)
+	}
+
+	opendexd, err := t.Context.GetService("opendexd")
+	if err != nil {
+		return err
+	}
+
+	token, err := t.getToken()
+	if err != nil {
+		return fmt.Errorf("get access token: %w", err)
+	}
+	t.Token = token
+
+	// only the certificates are mounted, the data dirs hold the keys
+	t.files = nil
+	t.mountFile(filepath.Join(opendexd.GetDataDir(), "tls.cert"), "/root/.opendex/tls.cert")
+
+	t.Environment["PORT"] = fmt.Sprintf("%d", containerPort)
+	t.Environment["PROXY_URL"] = p.GetInternalUri()
+	if p.RpcParams.Scheme == "https" {
+		certPath := fmt.Sprintf("/root/.proxy/%s", proxy.TlsCertFile)
+		t.mountFile(p.GetTlsCertPath(), certPath)
+		// the gateway also trusts the previous certificate after a rotation
+		if previous := p.GetPreviousTlsCertPath(); utils.FileExists(previous) {
+			t.mountFile(previous, certPath+".old")
+		}
+		t.Environment["PROXY_TLS_CERT"] = certPath
+	}
+	t.SetSecret("ACCESS_TOKEN", token)
+
+	t.Ports = append(t.Ports, fmt.Sprintf("127.0.0.1:%d:%d", t.Port, containerPort))
+
 	return nil
 }
+
+// mountFile mounts the host file read-only at path in the container
+func (t *Service) mountFile(file string, path string) {
+	t.files = append(t.files, file)
+	t.Volumes = append(t.Volumes, fmt.Sprintf("%s:%s:ro", file, path))
+}
+
+// Up starts the container once the mounted files exist, Docker would create
+// directories in their place otherwise
+func (t *Service) Up(ctx context.Context) error {
+	for _, file := range t.files {
+		if !utils.FileExists(file) {
+			return fmt.Errorf("%s doesn't exist yet", file)
+		}
+	}
+	return t.Base.Up(ctx)
+}
+
+// GetUrl returns the local webui URL with the access token
+func (t *Service) GetUrl() string {
+	u := url.URL{
+		Scheme:   "http",
+		Host:     fmt.Sprintf("127.0.0.1:%d", t.Port),
+		Path:     "/",
+		RawQuery: url.Values{"token": {t.Token}}.Encode(),
+	}
+	return u.String()
+}
+
+func (t *Service) GetStatus(ctx context.Context) (string, error) {
+	status, err := t.Base.GetStatus(ctx)
+	if err != nil {
+		return "", err
+	}
+	if status != "Container running" {
+		return status, nil
+	}
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", t.Port), 3*time.Second)
+	if err != nil {
+		return "Starting...", nil
+	}
+	_ = conn.Close()
+	return "Ready", nil
+}
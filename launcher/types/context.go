@@ -7,4 +7,12 @@ type Context interface {
 	GetExternalIp() string
 	GetBackupDir() string
 	GetDataDir() string
+	GetSecretStore() SecretStore
+}
+
+// SecretStore keeps secrets which are not given on every launch encrypted
+type SecretStore interface {
+	Get(name string) (string, error)
+	Put(name string, value string) error
+	Delete(name string) error
 }
@@ -19,6 +19,8 @@ type Service interface {
 	GetHostname() string
 	GetCommand() []string
 	GetEnvironment() map[string]string
+	GetSecretEnvironment() map[string]string
+	GetRuntimeEnvironment() map[string]string
 	GetPorts() []string
 	GetVolumes() []string
 	IsDisabled() bool
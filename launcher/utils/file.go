@@ -1,6 +1,9 @@
 package utils
 
-import "os"
+import (
+	"io/ioutil"
+	"os"
+)
 
 func FileExists(path string) bool {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -16,3 +19,13 @@ func IsDir(path string) bool {
 	}
 	return info.IsDir()
 }
+
+// WriteFileSecure writes data into a file which is only readable and writable
+// by the current user. The permission of an existing file is fixed as well.
+func WriteFileSecure(path string, data []byte) error {
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0600)
+}
+
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// Resolvable tells whether host is an IP address or a name this machine can
+// resolve. Names of the docker network, which the containers resolve, are
+// unknown on the host.
+func Resolvable(ctx context.Context, host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	return err == nil && len(addrs) > 0
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+var (
+	alphanumericRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+)
+
+// RandomString returns a cryptographically secure random alphanumeric string
+func RandomString(length int) (string, error) {
+	b := make([]rune, length)
+	n := big.NewInt(int64(len(alphanumericRunes)))
+	for i := range b {
+		k, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphanumericRunes[k.Int64()]
+	}
+	return string(b), nil
+}